@@ -0,0 +1,298 @@
+// Package blogclient is a Go client for BlogService.
+//
+// It wraps the generated blogpb client with dialing, per-call timeouts,
+// pagination and error translation, so callers deal in posts and Go errors:
+//
+//	client, err := blogclient.New("localhost:50051", blogclient.WithRetries(3))
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//
+//	for post, err := range client.ListPosts(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(post.Title)
+//	}
+//
+// Errors the server reports in the response body are returned as *Error;
+// transport failures are gRPC status errors, as from the generated client.
+package blogclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"grpc-blog/proto/blogpb"
+)
+
+// ErrNotFound matches, with errors.Is, the error for an unknown post ID.
+var ErrNotFound = errors.New("post not found")
+
+// ErrSlugTaken matches, with errors.Is, the error for a slug another post
+// already has.
+var ErrSlugTaken = errors.New("slug already in use")
+
+// Error is an error the server returned in the error field of a response
+// rather than as a gRPC status.
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Is reports whether e is the server's form of ErrNotFound or ErrSlugTaken.
+func (e *Error) Is(target error) bool {
+	return (target == ErrNotFound || target == ErrSlugTaken) && e.Message == target.Error()
+}
+
+// Client calls BlogService. It is safe for concurrent use.
+type Client struct {
+	rpc      blogpb.BlogServiceClient
+	conn     *grpc.ClientConn // nil unless the Client dialed it
+	opts     options
+	callOpts []grpc.CallOption
+}
+
+// New connects to the BlogService at target, a gRPC target such as
+// "localhost:50051" or "dns:///blog.internal:50051". The connection is made
+// lazily, on the first call.
+func New(target string, opts ...Option) (*Client, error) {
+	o := applyOptions(opts)
+
+	conn, err := grpc.NewClient(target, o.grpcOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("blogclient: %w", err)
+	}
+
+	c := newClient(conn, o)
+	c.conn = conn
+	return c, nil
+}
+
+// NewFromConn returns a Client using conn, which the caller keeps and
+// closes. Options that configure the connection have no effect; dial conn
+// with DialOptions to apply them.
+func NewFromConn(conn grpc.ClientConnInterface, opts ...Option) *Client {
+	return newClient(conn, applyOptions(opts))
+}
+
+// DialOptions returns the gRPC dial options New would use for opts: TLS,
+// the service config, hedging and any WithDialOptions.
+func DialOptions(opts ...Option) []grpc.DialOption {
+	return applyOptions(opts).grpcOptions()
+}
+
+func newClient(conn grpc.ClientConnInterface, o options) *Client {
+	return &Client{
+		rpc:      blogpb.NewBlogServiceClient(conn),
+		opts:     o,
+		callOpts: o.callOptions(),
+	}
+}
+
+func applyOptions(opts []Option) options {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Close closes the connection dialed by New. It does nothing for a Client
+// from NewFromConn.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// CreatePost creates a post from the fields of post other than PostId and
+// returns it as stored, with its new PostId.
+func (c *Client) CreatePost(ctx context.Context, post *blogpb.BlogPost) (*blogpb.BlogPost, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.rpc.CreatePost(ctx, &blogpb.CreatePostRequest{
+		Title:           post.GetTitle(),
+		Content:         post.GetContent(),
+		Author:          post.GetAuthor(),
+		PublicationDate: post.GetPublicationDate(),
+		Tags:            post.GetTags(),
+		Slug:            post.GetSlug(),
+		ContentFormat:   post.GetContentFormat(),
+	}, c.callOpts...)
+	return onePost(resp, err)
+}
+
+// GetPost returns the post with the given ID, or an error matching
+// ErrNotFound.
+func (c *Client) GetPost(ctx context.Context, id string) (*blogpb.BlogPost, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.rpc.ReadPost(ctx, &blogpb.ReadPostRequest{PostId: id}, c.callOpts...)
+	return onePost(resp, err)
+}
+
+// UpdatePost replaces the title, content, author, tags, slug and content
+// format of the post with post.PostId, and its publication date if post
+// has one, and returns it as stored.
+func (c *Client) UpdatePost(ctx context.Context, post *blogpb.BlogPost) (*blogpb.BlogPost, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.rpc.UpdatePost(ctx, &blogpb.UpdatePostRequest{
+		PostId:          post.GetPostId(),
+		Title:           post.GetTitle(),
+		Content:         post.GetContent(),
+		Author:          post.GetAuthor(),
+		Tags:            post.GetTags(),
+		Slug:            post.GetSlug(),
+		ContentFormat:   post.GetContentFormat(),
+		PublicationDate: post.GetPublicationDate(),
+	}, c.callOpts...)
+	return onePost(resp, err)
+}
+
+// UpdatePostFields changes only the named fields of the post with
+// post.PostId, taking their values from post, and returns it as stored.
+// Fields have proto names such as "title" or "publication_date"; a named
+// field unset in post is cleared. The server applies the change in one
+// step, so concurrent updates of other fields are kept.
+func (c *Client) UpdatePostFields(ctx context.Context, post *blogpb.BlogPost, fields ...string) (*blogpb.BlogPost, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("blogclient: UpdatePostFields needs at least one field")
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.rpc.UpdatePost(ctx, &blogpb.UpdatePostRequest{
+		PostId:          post.GetPostId(),
+		Title:           post.GetTitle(),
+		Content:         post.GetContent(),
+		Author:          post.GetAuthor(),
+		Tags:            post.GetTags(),
+		Slug:            post.GetSlug(),
+		ContentFormat:   post.GetContentFormat(),
+		PublicationDate: post.GetPublicationDate(),
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: fields},
+	}, c.callOpts...)
+	return onePost(resp, err)
+}
+
+// DeletePost deletes the post with the given ID.
+func (c *Client) DeletePost(ctx context.Context, id string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.rpc.DeletePost(ctx, &blogpb.DeletePostRequest{PostId: id}, c.callOpts...)
+	if err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return &Error{Message: resp.Error}
+	}
+	return nil
+}
+
+// ListPosts iterates over every post, ordered by ID, fetching pages as it
+// goes. Each page request has its own timeout. Iteration stops after the
+// first error, which is yielded with a nil post.
+func (c *Client) ListPosts(ctx context.Context) iter.Seq2[*blogpb.BlogPost, error] {
+	return func(yield func(*blogpb.BlogPost, error) bool) {
+		token := ""
+		for {
+			posts, next, err := c.ListPage(ctx, c.opts.pageSize, token)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, post := range posts {
+				if !yield(post, nil) {
+					return
+				}
+			}
+			if next == "" {
+				return
+			}
+			token = next
+		}
+	}
+}
+
+// ListPage returns one page of up to size posts after token, which is empty
+// for the first page, and the token of the next page, empty after the last.
+func (c *Client) ListPage(ctx context.Context, size int32, token string) ([]*blogpb.BlogPost, string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.rpc.ReadAll(ctx, &blogpb.ReadAllRequest{PageSize: size, PageToken: token}, c.callOpts...)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.Error != "" {
+		return nil, "", &Error{Message: resp.Error}
+	}
+	return resp.Post, resp.NextPageToken, nil
+}
+
+// ExportPosts iterates over every post, ordered by ID, as one server
+// stream, which is cheaper than ListPosts for a whole store. The default
+// timeout does not apply: the stream lasts as long as ctx. Iteration stops
+// after the first error, which is yielded with a nil post.
+func (c *Client) ExportPosts(ctx context.Context) iter.Seq2[*blogpb.BlogPost, error] {
+	return func(yield func(*blogpb.BlogPost, error) bool) {
+		// Stopping early cancels the stream.
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := c.rpc.ExportPosts(ctx, &blogpb.ExportPostsRequest{}, c.callOpts...)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for {
+			post, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(post, nil) {
+				return
+			}
+		}
+	}
+}
+
+// withTimeout applies the default timeout unless ctx has a deadline.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.opts.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.opts.timeout)
+}
+
+func onePost(resp *blogpb.PostResponse, err error) (*blogpb.BlogPost, error) {
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, &Error{Message: resp.Error}
+	}
+	if len(resp.Post) != 1 {
+		return nil, fmt.Errorf("blogclient: expected one post, got %d", len(resp.Post))
+	}
+	return resp.Post[0], nil
+}
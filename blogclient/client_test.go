@@ -0,0 +1,229 @@
+package blogclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"grpc-blog/internal/app/blog"
+	grpctransport "grpc-blog/internal/transport/grpc"
+	"grpc-blog/proto/blogpb"
+)
+
+// newTestClient serves BlogService over bufconn behind interceptor, if
+// any, and returns a Client dialed to it.
+func newTestClient(t *testing.T, interceptor grpc.UnaryServerInterceptor, opts ...Option) *Client {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	var serverOpts []grpc.ServerOption
+	if interceptor != nil {
+		serverOpts = append(serverOpts, grpc.UnaryInterceptor(interceptor))
+	}
+	srv := grpc.NewServer(serverOpts...)
+	service := blog.NewService(zaptest.NewLogger(t), blog.NewMemoryStore())
+	blogpb.RegisterBlogServiceServer(srv, grpctransport.NewBlogGRPCServer(service))
+
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	opts = append(opts, WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})))
+	client, err := New("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestCRUD(t *testing.T) {
+	client := newTestClient(t, nil)
+	ctx := context.Background()
+
+	created, err := client.CreatePost(ctx, &blogpb.BlogPost{Title: "hello", Tags: []string{"go"}})
+	if err != nil || created.PostId == "" {
+		t.Fatalf("create: %v, %v", created, err)
+	}
+
+	created.Title = "renamed"
+	if updated, err := client.UpdatePost(ctx, created); err != nil || updated.Title != "renamed" {
+		t.Fatalf("update: %v, %v", updated, err)
+	}
+
+	if got, err := client.GetPost(ctx, created.PostId); err != nil || got.Title != "renamed" {
+		t.Fatalf("get: %v, %v", got, err)
+	}
+
+	patch := &blogpb.BlogPost{PostId: created.PostId, Author: "ann"}
+	if updated, err := client.UpdatePostFields(ctx, patch, "author"); err != nil || updated.Author != "ann" || updated.Title != "renamed" {
+		t.Fatalf("update fields: %v, %v", updated, err)
+	}
+	if _, err := client.UpdatePostFields(ctx, patch); err == nil {
+		t.Fatal("expected an error without fields")
+	}
+
+	if err := client.DeletePost(ctx, created.PostId); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	_, err = client.GetPost(ctx, created.PostId)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	var serverErr *Error
+	if !errors.As(err, &serverErr) || serverErr.Message != "post not found" {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+
+	if err := client.DeletePost(ctx, created.PostId); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound on delete, got %v", err)
+	}
+}
+
+func TestSlugTaken(t *testing.T) {
+	client := newTestClient(t, nil)
+	ctx := context.Background()
+
+	if _, err := client.CreatePost(ctx, &blogpb.BlogPost{Slug: "hello"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := client.CreatePost(ctx, &blogpb.BlogPost{Slug: "hello"}); !errors.Is(err, ErrSlugTaken) || errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrSlugTaken, got %v", err)
+	}
+}
+
+func TestListPostsFollowsPages(t *testing.T) {
+	var pages atomic.Int32
+	count := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if info.FullMethod == blogpb.BlogService_ReadAll_FullMethodName {
+			pages.Add(1)
+		}
+		return handler(ctx, req)
+	}
+
+	client := newTestClient(t, count, WithPageSize(2))
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		client.CreatePost(ctx, &blogpb.BlogPost{Title: "post"})
+	}
+
+	var ids []string
+	for post, err := range client.ListPosts(ctx) {
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		ids = append(ids, post.PostId)
+	}
+	if len(ids) != 5 || pages.Load() != 3 {
+		t.Fatalf("expected 5 posts over 3 pages, got %d over %d", len(ids), pages.Load())
+	}
+
+	// Stopping early fetches no further pages.
+	pages.Store(0)
+	for range client.ListPosts(ctx) {
+		break
+	}
+	if pages.Load() != 1 {
+		t.Fatalf("expected one page after break, got %d", pages.Load())
+	}
+}
+
+func TestExportPosts(t *testing.T) {
+	client := newTestClient(t, nil)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		client.CreatePost(ctx, &blogpb.BlogPost{Title: "post", Tags: []string{"a"}})
+	}
+
+	var posts []*blogpb.BlogPost
+	for post, err := range client.ExportPosts(ctx) {
+		if err != nil {
+			t.Fatalf("export: %v", err)
+		}
+		posts = append(posts, post)
+	}
+	if len(posts) != 3 || posts[0].Title != "post" || len(posts[0].Tags) != 1 {
+		t.Fatalf("expected the 3 posts, got %v", posts)
+	}
+
+	for range client.ExportPosts(ctx) {
+		break
+	}
+}
+
+func TestListPostsError(t *testing.T) {
+	fail := func(context.Context, any, *grpc.UnaryServerInfo, grpc.UnaryHandler) (any, error) {
+		return nil, status.Error(codes.PermissionDenied, "no")
+	}
+	client := newTestClient(t, fail)
+
+	n := 0
+	for post, err := range client.ListPosts(context.Background()) {
+		n++
+		if post != nil || status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("expected the status error, got %v, %v", post, err)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("expected iteration to stop after the error, got %d items", n)
+	}
+}
+
+func TestToken(t *testing.T) {
+	var got atomic.Value
+	capture := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		got.Store(md.Get("authorization"))
+		return handler(ctx, req)
+	}
+
+	client := newTestClient(t, capture, WithToken("s3cret"))
+	client.GetPost(context.Background(), "x")
+
+	if auth, _ := got.Load().([]string); len(auth) != 1 || auth[0] != "Bearer s3cret" {
+		t.Fatalf("expected bearer token, got %v", auth)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var calls atomic.Int32
+	flaky := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if calls.Add(1) < 3 {
+			return nil, status.Error(codes.Unavailable, "try again")
+		}
+		return handler(ctx, req)
+	}
+
+	client := newTestClient(t, flaky, WithRetries(3))
+	if _, err := client.GetPost(context.Background(), "x"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the third attempt to reach the service, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	hang := func(ctx context.Context, _ any, _ *grpc.UnaryServerInfo, _ grpc.UnaryHandler) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	client := newTestClient(t, hang, WithTimeout(50*time.Millisecond))
+	if _, err := client.GetPost(context.Background(), "x"); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package blogclient
+
+import (
+	"context"
+	"crypto/tls"
+	"maps"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Option configures a Client.
+//
+// WithTLS, WithServiceConfig, WithRetries, WithHedging, WithMethodTimeout
+// and WithDialOptions configure the connection: they apply to New and
+// DialOptions, not to the conn given to NewFromConn.
+type Option func(*options)
+
+type options struct {
+	tls           *tls.Config
+	token         string
+	serviceConfig ServiceConfig
+	timeout       time.Duration
+	pageSize      int32
+	dialOptions   []grpc.DialOption
+}
+
+func defaultOptions() options {
+	return options{
+		serviceConfig: DefaultServiceConfig(),
+		timeout:       10 * time.Second,
+		pageSize:      100,
+	}
+}
+
+// WithTLS connects over TLS. Without it the connection is plaintext, as the
+// server listens by default.
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *options) { o.tls = cfg }
+}
+
+// WithToken sends token as a bearer token in the authorization metadata of
+// every call.
+func WithToken(token string) Option {
+	return func(o *options) { o.token = token }
+}
+
+// WithServiceConfig replaces DefaultServiceConfig.
+func WithServiceConfig(sc ServiceConfig) Option {
+	return func(o *options) { o.serviceConfig = sc }
+}
+
+// WithRetries sets the attempts, first included, made for idempotent
+// calls (GetPost and ListPosts) that fail with a retryable code, or sent
+// when hedging. 1 disables retries.
+func WithRetries(attempts int) Option {
+	return func(o *options) {
+		o.serviceConfig.Retry.MaxAttempts = attempts
+		if h := o.serviceConfig.Hedging; h != nil {
+			hedging := *h
+			hedging.MaxAttempts = attempts
+			o.serviceConfig.Hedging = &hedging
+		}
+	}
+}
+
+// WithHedging hedges idempotent calls instead of retrying them.
+func WithHedging(p HedgingPolicy) Option {
+	return func(o *options) { o.serviceConfig.Hedging = &p }
+}
+
+// WithMethodTimeout bounds calls to method, such as "ReadAll", across all
+// attempts.
+func WithMethodTimeout(method string, d time.Duration) Option {
+	return func(o *options) {
+		o.serviceConfig.Timeouts = maps.Clone(o.serviceConfig.Timeouts)
+		if o.serviceConfig.Timeouts == nil {
+			o.serviceConfig.Timeouts = make(map[string]time.Duration)
+		}
+		o.serviceConfig.Timeouts[method] = d
+	}
+}
+
+// WithTimeout bounds each call whose context has no deadline; 0 leaves
+// such calls unbounded. The default is 10s.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+// WithPageSize sets how many posts ListPosts fetches per request. The
+// default is 100.
+func WithPageSize(n int32) Option {
+	return func(o *options) { o.pageSize = n }
+}
+
+// WithDialOptions adds gRPC dial options, applied after the client's own.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) { o.dialOptions = append(o.dialOptions, opts...) }
+}
+
+// grpcOptions translates o into gRPC dial options. The token is sent per
+// call instead, so it also applies to NewFromConn.
+func (o options) grpcOptions() []grpc.DialOption {
+	creds := insecure.NewCredentials()
+	if o.tls != nil {
+		creds = credentials.NewTLS(o.tls)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(o.serviceConfig.JSON()),
+	}
+	if h := o.serviceConfig.Hedging; h != nil {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(hedgingInterceptor(*h)))
+	}
+	return append(opts, o.dialOptions...)
+}
+
+// bearerToken is a credentials.PerRPCCredentials sending a static token.
+type bearerToken struct {
+	token  string
+	secure bool
+}
+
+func (b bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + b.token}, nil
+}
+
+// RequireTransportSecurity lets tokens go over plaintext only when the
+// client was not given TLS, for local development.
+func (b bearerToken) RequireTransportSecurity() bool { return b.secure }
+
+// callOptions returns the options added to every call.
+func (o options) callOptions() []grpc.CallOption {
+	if o.token == "" {
+		return nil
+	}
+	return []grpc.CallOption{grpc.PerRPCCredentials(bearerToken{token: o.token, secure: o.tls != nil})}
+}
@@ -0,0 +1,238 @@
+package blogclient
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"grpc-blog/proto/blogpb"
+)
+
+// idempotentMethods may be sent more than once: they are retried or hedged.
+var idempotentMethods = []string{"ReadPost", "ReadAll"}
+
+// ServiceConfig is the gRPC service config a Client dials with: how
+// idempotent calls are retried or hedged and how long each method may take.
+type ServiceConfig struct {
+	// Retry applies to idempotent methods unless Hedging is set.
+	Retry RetryPolicy
+	// Hedging, if set, replaces Retry for idempotent methods.
+	Hedging *HedgingPolicy
+	// Timeouts bounds calls per method name, such as "ReadAll", across
+	// all attempts. The context deadline still applies if it is earlier.
+	Timeouts map[string]time.Duration
+}
+
+// RetryPolicy retries calls failing with one of Codes. Before each retry
+// gRPC waits a random time between zero and the current backoff, which
+// starts at InitialBackoff and grows by Multiplier up to MaxBackoff.
+// Retries are throttled when most calls to the server fail.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; 1 disables retries. gRPC caps
+	// it at 5.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Codes          []codes.Code
+}
+
+// HedgingPolicy sends up to MaxAttempts copies of a call, Delay apart,
+// and returns the first success. A failure with one of NonFatalCodes sends
+// the next copy at once; any other failure ends the call.
+//
+// gRPC-Go does not implement hedging from the service config, so the
+// Client does it in an interceptor.
+type HedgingPolicy struct {
+	MaxAttempts   int
+	Delay         time.Duration
+	NonFatalCodes []codes.Code
+}
+
+// DefaultServiceConfig retries idempotent calls on UNAVAILABLE up to four
+// times with backoff from 100ms to 2s, and bounds reads to 5s (ReadAll to
+// 10s) and writes to 10s.
+func DefaultServiceConfig() ServiceConfig {
+	return ServiceConfig{
+		Retry: RetryPolicy{
+			MaxAttempts:    4,
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     2 * time.Second,
+			Multiplier:     2,
+			Codes:          []codes.Code{codes.Unavailable},
+		},
+		Timeouts: map[string]time.Duration{
+			"ReadPost":   5 * time.Second,
+			"ReadAll":    10 * time.Second,
+			"CreatePost": 10 * time.Second,
+			"UpdatePost": 10 * time.Second,
+			"DeletePost": 10 * time.Second,
+		},
+	}
+}
+
+// JSON renders sc in the gRPC service config format.
+func (sc ServiceConfig) JSON() string {
+	type name struct {
+		Service string `json:"service"`
+		Method  string `json:"method"`
+	}
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type methodConfig struct {
+		Name        []name       `json:"name"`
+		Timeout     string       `json:"timeout,omitempty"`
+		RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+	}
+	type throttling struct {
+		MaxTokens  float64 `json:"maxTokens"`
+		TokenRatio float64 `json:"tokenRatio"`
+	}
+
+	service := blogpb.BlogService_ServiceDesc.ServiceName
+	var methods []methodConfig
+	for _, md := range blogpb.BlogService_ServiceDesc.Methods {
+		mc := methodConfig{Name: []name{{Service: service, Method: md.MethodName}}}
+		if d := sc.Timeouts[md.MethodName]; d > 0 {
+			mc.Timeout = seconds(d)
+		}
+
+		r := sc.Retry
+		if sc.Hedging == nil && r.MaxAttempts > 1 && slices.Contains(idempotentMethods, md.MethodName) {
+			mc.RetryPolicy = &retryPolicy{
+				MaxAttempts:          r.MaxAttempts,
+				InitialBackoff:       seconds(r.InitialBackoff),
+				MaxBackoff:           seconds(r.MaxBackoff),
+				BackoffMultiplier:    r.Multiplier,
+				RetryableStatusCodes: codeNames(r.Codes),
+			}
+		}
+
+		if mc.Timeout != "" || mc.RetryPolicy != nil {
+			methods = append(methods, mc)
+		}
+	}
+
+	out, _ := json.Marshal(struct {
+		MethodConfig    []methodConfig `json:"methodConfig,omitempty"`
+		RetryThrottling throttling     `json:"retryThrottling"`
+	}{
+		MethodConfig:    methods,
+		RetryThrottling: throttling{MaxTokens: 10, TokenRatio: 0.1},
+	})
+	return string(out)
+}
+
+// seconds formats d as a service config duration, such as "0.1s".
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// serviceConfigCodes are the canonical status code names a service config
+// accepts. They do not always follow codes.Code.String: Canceled is
+// CANCELLED.
+var serviceConfigCodes = [...]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// codeNames returns the service config names of cs, such as "UNAVAILABLE".
+// An unknown code keeps its String form, which gRPC rejects when dialing.
+func codeNames(cs []codes.Code) []string {
+	names := make([]string, len(cs))
+	for i, c := range cs {
+		if int(c) < len(serviceConfigCodes) {
+			names[i] = serviceConfigCodes[c]
+		} else {
+			names[i] = c.String()
+		}
+	}
+	return names
+}
+
+// hedgingInterceptor hedges the idempotent methods according to p.
+func hedgingInterceptor(p HedgingPolicy) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		name := method[strings.LastIndex(method, "/")+1:]
+		if p.MaxAttempts <= 1 || !slices.Contains(idempotentMethods, name) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		// Losing attempts are cancelled once the call returns.
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type result struct {
+			reply proto.Message
+			err   error
+		}
+		results := make(chan result, p.MaxAttempts)
+		sent := 0
+		send := func() {
+			out := reply.(proto.Message).ProtoReflect().New().Interface()
+			sent++
+			go func() { results <- result{out, invoker(ctx, method, req, out, cc, opts...)} }()
+		}
+
+		send()
+		timer := time.NewTimer(p.Delay)
+		defer timer.Stop()
+
+		var err error
+		for received := 0; received < sent; {
+			select {
+			case <-timer.C:
+				if sent < p.MaxAttempts {
+					send()
+					timer.Reset(p.Delay)
+				}
+
+			case res := <-results:
+				received++
+				if res.err == nil {
+					proto.Reset(reply.(proto.Message))
+					proto.Merge(reply.(proto.Message), res.reply)
+					return nil
+				}
+
+				err = res.err
+				if !slices.Contains(p.NonFatalCodes, status.Code(err)) {
+					return err
+				}
+				if sent < p.MaxAttempts {
+					send()
+					timer.Reset(p.Delay)
+				}
+			}
+		}
+		return err
+	}
+}
@@ -0,0 +1,258 @@
+package blogclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-blog/proto/blogpb"
+)
+
+type fault int
+
+const (
+	serve fault = iota
+	unavailable
+	internal
+	stall // block until the attempt is cancelled
+)
+
+// faults is a server interceptor that injects the fault plan returns for
+// the nth attempt, counting from 1, at each method.
+type faults struct {
+	plan func(method string, n int) fault
+
+	mu        sync.Mutex
+	calls     map[string]int
+	cancelled chan struct{}
+}
+
+func newFaults(plan func(method string, n int) fault) *faults {
+	return &faults{plan: plan, calls: map[string]int{}, cancelled: make(chan struct{}, 10)}
+}
+
+func (f *faults) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+
+	f.mu.Lock()
+	f.calls[method]++
+	n := f.calls[method]
+	f.mu.Unlock()
+
+	switch f.plan(method, n) {
+	case unavailable:
+		return nil, status.Error(codes.Unavailable, "injected")
+	case internal:
+		return nil, status.Error(codes.Internal, "injected")
+	case stall:
+		<-ctx.Done()
+		f.cancelled <- struct{}{}
+		return nil, ctx.Err()
+	}
+	return handler(ctx, req)
+}
+
+func (f *faults) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func TestDefaultConfigRetriesReads(t *testing.T) {
+	f := newFaults(func(_ string, n int) fault {
+		if n < 3 {
+			return unavailable
+		}
+		return serve
+	})
+	client := newTestClient(t, f.intercept)
+
+	if _, err := client.GetPost(context.Background(), "x"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the third attempt to be served, got %v", err)
+	}
+	if got := f.count("ReadPost"); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRetriesGiveUp(t *testing.T) {
+	f := newFaults(func(string, int) fault { return unavailable })
+	client := newTestClient(t, f.intercept, WithRetries(3))
+
+	if _, err := client.GetPost(context.Background(), "x"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if got := f.count("ReadPost"); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestNotRetried(t *testing.T) {
+	f := newFaults(func(method string, n int) fault {
+		if method == "ReadAll" {
+			return internal
+		}
+		return unavailable
+	})
+	client := newTestClient(t, f.intercept)
+	ctx := context.Background()
+
+	// Writes are not idempotent.
+	if _, err := client.CreatePost(ctx, &blogpb.BlogPost{Title: "x"}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if got := f.count("CreatePost"); got != 1 {
+		t.Fatalf("expected CreatePost to be sent once, got %d", got)
+	}
+
+	// INTERNAL is not retryable.
+	if _, _, err := client.ListPage(ctx, 10, ""); status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+	if got := f.count("ReadAll"); got != 1 {
+		t.Fatalf("expected ReadAll to be sent once, got %d", got)
+	}
+}
+
+func TestMethodTimeout(t *testing.T) {
+	f := newFaults(func(string, int) fault { return stall })
+	client := newTestClient(t, f.intercept, WithTimeout(0), WithMethodTimeout("ReadPost", 50*time.Millisecond))
+
+	start := time.Now()
+	if _, err := client.GetPost(context.Background(), "x"); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("method timeout not applied, call took %v", elapsed)
+	}
+}
+
+func TestHedging(t *testing.T) {
+	f := newFaults(func(_ string, n int) fault {
+		if n == 1 {
+			return stall
+		}
+		return serve
+	})
+	client := newTestClient(t, f.intercept, WithHedging(HedgingPolicy{MaxAttempts: 3, Delay: 20 * time.Millisecond}))
+
+	start := time.Now()
+	if _, err := client.GetPost(context.Background(), "x"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the hedge to be served, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the hedge to answer quickly, took %v", elapsed)
+	}
+	if got := f.count("ReadPost"); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+
+	select {
+	case <-f.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the stalled attempt to be cancelled")
+	}
+}
+
+func TestHedgingFailures(t *testing.T) {
+	f := newFaults(func(method string, n int) fault {
+		switch {
+		case method == "ReadAll":
+			return internal
+		case n == 1:
+			return unavailable
+		}
+		return serve
+	})
+	client := newTestClient(t, f.intercept, WithHedging(HedgingPolicy{
+		MaxAttempts:   3,
+		Delay:         time.Minute,
+		NonFatalCodes: []codes.Code{codes.Unavailable},
+	}))
+	ctx := context.Background()
+
+	// A non-fatal failure sends the next attempt without waiting for Delay.
+	if _, err := client.GetPost(ctx, "x"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the second attempt to be served, got %v", err)
+	}
+	if got := f.count("ReadPost"); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+
+	// A fatal failure ends the call.
+	if _, _, err := client.ListPage(ctx, 10, ""); status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+	if got := f.count("ReadAll"); got != 1 {
+		t.Fatalf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestServiceConfigJSON(t *testing.T) {
+	sc := DefaultServiceConfig()
+	sc.Retry.Codes = append(sc.Retry.Codes, codes.DeadlineExceeded)
+
+	var parsed struct {
+		MethodConfig []struct {
+			Name []struct {
+				Service, Method string
+			}
+			Timeout     string
+			RetryPolicy *struct {
+				MaxAttempts          int
+				InitialBackoff       string
+				RetryableStatusCodes []string
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(sc.JSON()), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	retried := map[string]bool{}
+	for _, mc := range parsed.MethodConfig {
+		name := mc.Name[0]
+		if name.Service != "blog.BlogService" || mc.Timeout == "" {
+			t.Errorf("unexpected method config %+v", mc)
+		}
+		if p := mc.RetryPolicy; p != nil {
+			retried[name.Method] = true
+			if p.MaxAttempts != 4 || p.InitialBackoff != "0.1s" || strings.Join(p.RetryableStatusCodes, ",") != "UNAVAILABLE,DEADLINE_EXCEEDED" {
+				t.Errorf("unexpected retry policy %+v", p)
+			}
+		}
+	}
+	if len(retried) != 2 || !retried["ReadPost"] || !retried["ReadAll"] {
+		t.Fatalf("expected only ReadPost and ReadAll to retry, got %v", retried)
+	}
+
+	sc.Hedging = &HedgingPolicy{MaxAttempts: 2, Delay: time.Millisecond}
+	if strings.Contains(sc.JSON(), "retryPolicy") {
+		t.Fatal("expected hedging to replace the retry policy")
+	}
+}
+
+func TestServiceConfigAcceptsEveryCode(t *testing.T) {
+	sc := DefaultServiceConfig()
+	sc.Retry.Codes = nil
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		sc.Retry.Codes = append(sc.Retry.Codes, c)
+	}
+	if !strings.Contains(sc.JSON(), `"CANCELLED"`) {
+		t.Fatalf("expected the canonical name of Canceled, got %s", sc.JSON())
+	}
+
+	client, err := New("passthrough:///bufnet", WithServiceConfig(sc))
+	if err != nil {
+		t.Fatalf("expected the service config to be accepted: %v", err)
+	}
+	client.Close()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"grpc-blog/proto/blogpb"
+)
+
+const backupUsage = `usage:
+  client backup create            snapshot every post to a new file on the server
+  client backup list              list the server's backups, newest first
+  client backup restore <name>    replace every post with those in a backup
+
+The server must be started with backup.dir and admin.addr set; the client
+reaches it at -admin-addr with the admin token from BLOG_ADMIN_TOKEN.`
+
+// backup implements the "backup" subcommand over AdminService.
+func (c *cli) backup(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageErrorf(backupUsage)
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "create", "list", "restore":
+	case "-h", "-help", "--help", "help":
+		fmt.Fprintln(c.stderr, backupUsage)
+		return flag.ErrHelp
+	default:
+		return usageErrorf("unknown backup command %q\n\n%s", command, backupUsage)
+	}
+	if c.adminToken == "" {
+		return errors.New("backup: BLOG_ADMIN_TOKEN is not set")
+	}
+
+	conn, err := grpc.NewClient(c.adminAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	admin := blogpb.NewAdminServiceClient(conn)
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.adminToken)
+	switch command {
+	case "create":
+		fs := c.flagSet("backup create", "")
+		format := bindBackupOutput(fs)
+		if err := c.parseBackup(fs, args, 0, format); err != nil {
+			return err
+		}
+		b, err := admin.CreateBackup(ctx, &blogpb.CreateBackupRequest{})
+		if err != nil {
+			return err
+		}
+		return c.printBackups(*format, []*blogpb.Backup{b}, false)
+	case "list":
+		fs := c.flagSet("backup list", "")
+		format := bindBackupOutput(fs)
+		if err := c.parseBackup(fs, args, 0, format); err != nil {
+			return err
+		}
+		resp, err := admin.ListBackups(ctx, &blogpb.ListBackupsRequest{})
+		if err != nil {
+			return err
+		}
+		return c.printBackups(*format, resp.Backups, true)
+	default: // "restore"
+		fs := c.flagSet("backup restore", "<name>")
+		format := bindBackupOutput(fs)
+		if err := c.parseBackup(fs, args, 1, format); err != nil {
+			return err
+		}
+		b, err := admin.RestoreBackup(ctx, &blogpb.RestoreBackupRequest{Name: fs.Arg(0)})
+		if err != nil {
+			return err
+		}
+		return c.printBackups(*format, []*blogpb.Backup{b}, false)
+	}
+}
+
+func bindBackupOutput(fs *flag.FlagSet) *string {
+	format := fs.String("output", outputTable, "output format: table or json")
+	fs.StringVar(format, "o", outputTable, "shorthand for -output")
+	return format
+}
+
+func (c *cli) parseBackup(fs *flag.FlagSet, args []string, nargs int, format *string) error {
+	if err := c.parse(fs, args, nargs); err != nil {
+		return err
+	}
+	if *format != outputTable && *format != outputJSON {
+		return usageErrorf("unknown -output %q", *format)
+	}
+	return nil
+}
+
+// printBackups writes backups as a table, or in the proto3 JSON mapping.
+func (c *cli) printBackups(format string, backups []*blogpb.Backup, list bool) error {
+	if format == outputJSON {
+		raw := make([]json.RawMessage, len(backups))
+		for i, b := range backups {
+			data, err := protojson.Marshal(b)
+			if err != nil {
+				return err
+			}
+			raw[i] = data
+		}
+
+		var v any = raw
+		if !list {
+			v = raw[0]
+		}
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.stdout, string(out))
+		return err
+	}
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCREATED\tPOSTS\tSIZE\tSHA256")
+	for _, b := range backups {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.12s\n", b.Name,
+			b.CreatedAt.AsTime().Format(time.RFC3339), b.PostCount, b.SizeBytes, b.Sha256)
+	}
+	return tw.Flush()
+}
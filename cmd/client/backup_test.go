@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBackup(t *testing.T) {
+	addr, adminAddr := startServers(t)
+	t.Setenv("BLOG_ADMIN_TOKEN", testAdminToken)
+	backup := func(args ...string) result {
+		return runClient(t, addr, "", append([]string{"-admin-addr", adminAddr, "backup"}, args...)...)
+	}
+	runClient(t, addr, "", "posts", "create", "-title", "Kept")
+
+	res := backup("create", "-o", "json")
+	if res.code != exitOK {
+		t.Fatalf("create: exit %d: %s", res.code, res.stderr)
+	}
+	var created struct {
+		Name      string `json:"name"`
+		PostCount string `json:"postCount"`
+	}
+	if err := json.Unmarshal([]byte(res.stdout), &created); err != nil || created.PostCount != "1" {
+		t.Fatalf("unexpected backup %s (%v)", res.stdout, err)
+	}
+
+	res = backup("list")
+	if res.code != exitOK || !strings.HasPrefix(res.stdout, "NAME") || !strings.Contains(res.stdout, created.Name) {
+		t.Fatalf("list: exit %d: %s%s", res.code, res.stdout, res.stderr)
+	}
+
+	runClient(t, addr, "", "posts", "create", "-title", "Dropped")
+	if res := backup("restore", created.Name); res.code != exitOK {
+		t.Fatalf("restore: exit %d: %s", res.code, res.stderr)
+	}
+	res = runClient(t, addr, "", "posts", "list", "-o", "table", "-columns", "title")
+	if !strings.Contains(res.stdout, "Kept") || strings.Contains(res.stdout, "Dropped") {
+		t.Fatalf("expected only the backed up post, got %s", res.stdout)
+	}
+
+	res = backup("restore", "missing.backup")
+	if res.code != exitError || !strings.Contains(res.stderr, "NotFound: backup not found") {
+		t.Fatalf("expected NotFound, got exit %d: %s", res.code, res.stderr)
+	}
+	if res := backup("list", "-o", "yaml"); res.code != exitUsage {
+		t.Fatalf("expected a usage error for -o yaml, got exit %d", res.code)
+	}
+
+	t.Setenv("BLOG_ADMIN_TOKEN", "wrong")
+	if res := backup("list"); res.code != exitError || !strings.Contains(res.stderr, "Unauthenticated") {
+		t.Fatalf("expected a wrong token to be refused, got exit %d: %s", res.code, res.stderr)
+	}
+	// AdminService is not on the public port.
+	res = runClient(t, addr, "", "-admin-addr", addr, "backup", "list")
+	if res.code != exitError || !strings.Contains(res.stderr, "Unimplemented") {
+		t.Fatalf("expected AdminService to be missing on the public port, got exit %d: %s", res.code, res.stderr)
+	}
+}
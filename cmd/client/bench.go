@@ -0,0 +1,527 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"grpc-blog/blogclient"
+	"grpc-blog/proto/blogpb"
+)
+
+// benchOps are the operations a benchmark mixes, in report order.
+var benchOps = []string{"create", "read", "list", "update", "delete"}
+
+const defaultMix = "create=10,read=70,list=5,update=10,delete=5"
+
+// Latencies are recorded in microseconds, from 1µs to a minute, to three
+// significant figures.
+const (
+	minLatency = 1
+	maxLatency = int64(time.Minute / time.Microsecond)
+)
+
+// maxRate bounds -rate so that operations are due at least a microsecond
+// apart.
+const maxRate = 1_000_000
+
+// errNoPosts is recorded when an operation needs a post and none is left.
+var errNoPosts = errors.New("no posts left")
+
+// bench implements the "bench" subcommand. It seeds posts, then runs a mix
+// of operations from -concurrency workers until -duration or -requests is
+// reached, optionally paced to -rate operations per second, and reports
+// throughput and latency percentiles per operation.
+//
+// -timeout applies to each RPC rather than to the whole run.
+func (c *cli) bench(ctx context.Context, args []string) error {
+	fs := c.flagSet("bench", "[flags]")
+	duration := fs.Duration("duration", 10*time.Second, "how long to run, 0 for no limit")
+	requests := fs.Int64("requests", 0, "stop after this many operations, 0 for no limit")
+	concurrency := fs.Int("concurrency", 8, "workers sending operations")
+	rate := fs.Float64("rate", 0, "target operations per second across workers, 0 to send as fast as the workers can")
+	mixFlag := fs.String("mix", defaultMix, "relative weights of create, read, list, update and delete")
+	seed := fs.Int("seed", 100, "posts to create before measuring")
+	contentSize := fs.Int("content-size", 1024, "bytes of content in each created or updated post")
+	pageSize := fs.Int("page-size", 0, "page size of list operations, 0 for the whole store")
+	output := fs.String("output", "text", "report format: text or json")
+	fs.StringVar(output, "o", "text", "shorthand for -output")
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	mix, err := parseMix(*mixFlag)
+	if err != nil {
+		return err
+	}
+	switch {
+	case *duration <= 0 && *requests <= 0:
+		return usageErrorf("one of -duration and -requests must be set")
+	case *concurrency < 1:
+		return usageErrorf("-concurrency must be at least 1")
+	case !(*rate >= 0 && *rate <= maxRate): // also rejects NaN
+		return usageErrorf("-rate must be from 0 to %d operations per second", maxRate)
+	case *seed < 0 || *contentSize < 0 || *pageSize < 0:
+		return usageErrorf("-seed, -content-size and -page-size must not be negative")
+	case *output != "text" && *output != outputJSON:
+		return usageErrorf("unknown -output %q; want text or json", *output)
+	}
+
+	b := &benchmark{
+		client:   c.blog,
+		timeout:  c.timeout,
+		mix:      mix,
+		content:  strings.Repeat("x", *contentSize),
+		pageSize: int32(*pageSize),
+		requests: *requests,
+	}
+	if err := b.seed(ctx, *seed, *concurrency); err != nil {
+		return fmt.Errorf("seed: %w", err)
+	}
+
+	report := b.run(ctx, *duration, *concurrency, *rate)
+	report.Seeded = *seed
+	if *output == outputJSON {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.stdout, string(out))
+		return nil
+	}
+	return report.print(c)
+}
+
+// benchMix picks operations at random in proportion to their weights.
+type benchMix struct {
+	ops     []string
+	weights []int
+	total   int
+}
+
+// parseMix parses weights such as "read=80,create=20". Operations left out
+// are not run.
+func parseMix(s string) (benchMix, error) {
+	var m benchMix
+	for _, part := range strings.Split(s, ",") {
+		op, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || !slices.Contains(benchOps, op) {
+			return m, usageErrorf("invalid -mix %q: want op=weight pairs with ops %s", s, strings.Join(benchOps, ", "))
+		}
+		w, err := strconv.Atoi(weight)
+		if err != nil || w < 0 {
+			return m, usageErrorf("invalid -mix weight %q for %s", weight, op)
+		}
+		if slices.Contains(m.ops, op) {
+			return m, usageErrorf("invalid -mix: %s given twice", op)
+		}
+		m.ops = append(m.ops, op)
+		m.weights = append(m.weights, w)
+		m.total += w
+	}
+	if m.total == 0 {
+		return m, usageErrorf("invalid -mix %q: the weights add up to zero", s)
+	}
+	return m, nil
+}
+
+func (m benchMix) pick() string {
+	n := rand.IntN(m.total)
+	for i, w := range m.weights {
+		if n < w {
+			return m.ops[i]
+		}
+		n -= w
+	}
+	return m.ops[len(m.ops)-1]
+}
+
+// benchmark holds the state shared by the workers of a run.
+type benchmark struct {
+	client   *blogclient.Client
+	timeout  time.Duration
+	mix      benchMix
+	content  string
+	pageSize int32
+	requests int64
+
+	ids    postIDs
+	issued atomic.Int64
+}
+
+// seed creates n posts from up to concurrency workers.
+func (b *benchmark) seed(ctx context.Context, n, concurrency int) error {
+	var (
+		next     atomic.Int64
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for range min(n, concurrency) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for next.Add(1) <= int64(n) {
+				if err := b.do(ctx, "create"); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// run drives the workers and merges what they recorded.
+func (b *benchmark) run(ctx context.Context, duration time.Duration, concurrency int, rate float64) *benchReport {
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	if duration > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, duration)
+		defer cancel()
+	}
+
+	var schedule <-chan time.Time
+	if rate > 0 {
+		schedule = paced(runCtx, rate)
+	}
+
+	start := time.Now()
+	stats := make([]*benchStats, concurrency)
+	var wg sync.WaitGroup
+	for i := range stats {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stats[i] = b.worker(ctx, runCtx, schedule)
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	total := newBenchStats()
+	for _, s := range stats {
+		total.merge(s)
+	}
+	return total.report(elapsed, concurrency, rate)
+}
+
+// worker sends operations until runCtx is done or the request limit is
+// reached. RPCs use ctx, so the end of the run does not cancel calls in
+// flight.
+func (b *benchmark) worker(ctx, runCtx context.Context, schedule <-chan time.Time) *benchStats {
+	stats := newBenchStats()
+	for runCtx.Err() == nil {
+		if b.requests > 0 && b.issued.Add(1) > b.requests {
+			break
+		}
+
+		start := time.Now()
+		if schedule != nil {
+			var ok bool
+			if start, ok = <-schedule; !ok {
+				break
+			}
+		}
+
+		op := b.mix.pick()
+		err := b.do(ctx, op)
+		stats.record(op, time.Since(start), err)
+	}
+	return stats
+}
+
+// paced sends the times operations are due, rate per second, until ctx is
+// done. Workers measure latency from the due time rather than from when
+// they got to send, so a slow server is not hidden by the workers falling
+// behind (coordinated omission).
+func paced(ctx context.Context, rate float64) <-chan time.Time {
+	schedule := make(chan time.Time, 1024)
+	interval := time.Duration(float64(time.Second) / rate)
+	go func() {
+		defer close(schedule)
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
+		start := time.Now()
+		for i := 0; ; i++ {
+			due := start.Add(time.Duration(i) * interval)
+			timer.Reset(time.Until(due))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case schedule <- due:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return schedule
+}
+
+// do performs one operation under the per-RPC timeout.
+func (b *benchmark) do(ctx context.Context, op string) error {
+	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	switch op {
+	case "create":
+		post, err := b.client.CreatePost(ctx, b.post())
+		if err != nil {
+			return err
+		}
+		b.ids.add(post.PostId)
+		return nil
+
+	case "read":
+		id, ok := b.ids.pick()
+		if !ok {
+			return errNoPosts
+		}
+		_, err := b.client.GetPost(ctx, id)
+		return err
+
+	case "list":
+		_, _, err := b.client.ListPage(ctx, b.pageSize, "")
+		return err
+
+	case "update":
+		id, ok := b.ids.pick()
+		if !ok {
+			return errNoPosts
+		}
+		post := b.post()
+		post.PostId = id
+		_, err := b.client.UpdatePost(ctx, post)
+		return err
+
+	case "delete":
+		id, ok := b.ids.take()
+		if !ok {
+			return errNoPosts
+		}
+		return b.client.DeletePost(ctx, id)
+	}
+	return fmt.Errorf("unknown operation %q", op)
+}
+
+func (b *benchmark) post() *blogpb.BlogPost {
+	return &blogpb.BlogPost{
+		Title:           "bench post",
+		Content:         b.content,
+		Author:          "bench",
+		PublicationDate: timestamppb.Now(),
+		Tags:            []string{"bench"},
+	}
+}
+
+// postIDs are the posts the benchmark created and has not deleted.
+type postIDs struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (p *postIDs) add(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ids = append(p.ids, id)
+}
+
+// pick returns a random ID.
+func (p *postIDs) pick() (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.ids) == 0 {
+		return "", false
+	}
+	return p.ids[rand.IntN(len(p.ids))], true
+}
+
+// take removes and returns a random ID.
+func (p *postIDs) take() (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.ids) == 0 {
+		return "", false
+	}
+	i := rand.IntN(len(p.ids))
+	id := p.ids[i]
+	p.ids[i] = p.ids[len(p.ids)-1]
+	p.ids = p.ids[:len(p.ids)-1]
+	return id, true
+}
+
+// benchStats records the outcome of operations. Each worker has its own,
+// merged at the end, since histograms are not safe for concurrent use.
+type benchStats struct {
+	latency map[string]*hdrhistogram.Histogram // successful calls
+	errors  map[string]map[string]int64        // by op, then error
+}
+
+func newBenchStats() *benchStats {
+	s := &benchStats{latency: map[string]*hdrhistogram.Histogram{}, errors: map[string]map[string]int64{}}
+	for _, op := range benchOps {
+		s.latency[op] = hdrhistogram.New(minLatency, maxLatency, 3)
+		s.errors[op] = map[string]int64{}
+	}
+	return s
+}
+
+func (s *benchStats) record(op string, latency time.Duration, err error) {
+	if err != nil {
+		s.errors[op][errorKey(err)]++
+		return
+	}
+	us := min(max(latency.Microseconds(), minLatency), maxLatency)
+	s.latency[op].RecordValue(us)
+}
+
+func (s *benchStats) merge(other *benchStats) {
+	for _, op := range benchOps {
+		s.latency[op].Merge(other.latency[op])
+		for key, n := range other.errors[op] {
+			s.errors[op][key] += n
+		}
+	}
+}
+
+// errorKey groups errors: gRPC status errors by code, others by message.
+func errorKey(err error) string {
+	if st, ok := status.FromError(err); ok {
+		return st.Code().String()
+	}
+	return err.Error()
+}
+
+// benchReport is the result of a run. Throughput and latencies count
+// successful calls only; failures are counted by error.
+type benchReport struct {
+	Duration    float64    `json:"duration_seconds"`
+	Concurrency int        `json:"concurrency"`
+	Rate        float64    `json:"target_rate,omitempty"`
+	Seeded      int        `json:"seeded"`
+	Operations  []opReport `json:"operations"`
+	Total       opReport   `json:"total"`
+}
+
+type opReport struct {
+	Op         string           `json:"op"`
+	Count      int64            `json:"count"`
+	Errors     int64            `json:"errors"`
+	Throughput float64          `json:"throughput"`
+	Latency    latencyReport    `json:"latency_ms"`
+	ErrorsBy   map[string]int64 `json:"errors_by,omitempty"`
+}
+
+type latencyReport struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	P999 float64 `json:"p99_9"`
+	Max  float64 `json:"max"`
+}
+
+func (s *benchStats) report(elapsed time.Duration, concurrency int, rate float64) *benchReport {
+	r := &benchReport{Duration: elapsed.Seconds(), Concurrency: concurrency, Rate: rate}
+
+	all := hdrhistogram.New(minLatency, maxLatency, 3)
+	allErrors := map[string]int64{}
+	for _, op := range benchOps {
+		hist := s.latency[op]
+		if hist.TotalCount() == 0 && len(s.errors[op]) == 0 {
+			continue
+		}
+		all.Merge(hist)
+		for key, n := range s.errors[op] {
+			allErrors[op+": "+key] += n
+		}
+		r.Operations = append(r.Operations, newOpReport(op, hist, s.errors[op], elapsed))
+	}
+	r.Total = newOpReport("total", all, allErrors, elapsed)
+	return r
+}
+
+func newOpReport(op string, hist *hdrhistogram.Histogram, errs map[string]int64, elapsed time.Duration) opReport {
+	ms := func(us int64) float64 { return float64(us) / 1000 }
+	r := opReport{
+		Op:         op,
+		Count:      hist.TotalCount(),
+		Throughput: float64(hist.TotalCount()) / elapsed.Seconds(),
+		Latency: latencyReport{
+			Mean: hist.Mean() / 1000,
+			P50:  ms(hist.ValueAtQuantile(50)),
+			P90:  ms(hist.ValueAtQuantile(90)),
+			P99:  ms(hist.ValueAtQuantile(99)),
+			P999: ms(hist.ValueAtQuantile(99.9)),
+			Max:  ms(hist.Max()),
+		},
+	}
+	if len(errs) > 0 {
+		r.ErrorsBy = errs
+	}
+	for _, n := range errs {
+		r.Errors += n
+	}
+	return r
+}
+
+// print writes r as a table, followed by the errors if there were any.
+func (r *benchReport) print(c *cli) error {
+	pace := "unpaced"
+	if r.Rate > 0 {
+		pace = fmt.Sprintf("target %g/s", r.Rate)
+	}
+	fmt.Fprintf(c.stdout, "%d posts seeded; ran %.1fs with %d workers, %s\n\n", r.Seeded, r.Duration, r.Concurrency, pace)
+
+	tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "OP\tOK\tERRORS\tOPS/S\tMEAN\tP50\tP90\tP99\tP99.9\tMAX\t")
+	for _, op := range append(r.Operations, r.Total) {
+		l := op.Latency
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t%s\t%s\t\n", op.Op, op.Count, op.Errors, op.Throughput,
+			millis(l.Mean), millis(l.P50), millis(l.P90), millis(l.P99), millis(l.P999), millis(l.Max))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.Total.ErrorsBy) > 0 {
+		fmt.Fprintln(c.stdout, "\nerrors:")
+		keys := make([]string, 0, len(r.Total.ErrorsBy))
+		for key := range r.Total.ErrorsBy {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			fmt.Fprintf(c.stdout, "  %d × %s\n", r.Total.ErrorsBy[key], key)
+		}
+	}
+	return nil
+}
+
+// millis formats a latency in milliseconds as a duration, such as "1.25ms".
+func millis(ms float64) string {
+	return time.Duration(ms * float64(time.Millisecond)).Round(time.Microsecond).String()
+}
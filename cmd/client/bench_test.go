@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodeReport(t *testing.T, out string) benchReport {
+	t.Helper()
+
+	var report benchReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("output is not a report: %v\n%s", err, out)
+	}
+	return report
+}
+
+func TestBenchRequests(t *testing.T) {
+	addr := startServer(t)
+
+	// Without deletes no operation can race for a post.
+	res := runClient(t, addr, "", "bench", "-requests", "60", "-concurrency", "4",
+		"-seed", "10", "-mix", "create=1,read=3,list=1,update=1", "-o", "json")
+	if res.code != exitOK {
+		t.Fatalf("bench: exit %d: %s", res.code, res.stderr)
+	}
+
+	report := decodeReport(t, res.stdout)
+	if report.Total.Count != 60 || report.Total.Errors != 0 || report.Seeded != 10 {
+		t.Fatalf("expected 60 successful operations, got %+v", report.Total)
+	}
+	var sum int64
+	for _, op := range report.Operations {
+		if op.Op == "delete" {
+			t.Fatal("expected no deletes")
+		}
+		if op.Count > 0 && (op.Latency.P50 <= 0 || op.Latency.Max < op.Latency.P99) {
+			t.Fatalf("implausible latencies for %s: %+v", op.Op, op.Latency)
+		}
+		sum += op.Count
+	}
+	if sum != report.Total.Count {
+		t.Fatalf("operations add up to %d, total is %d", sum, report.Total.Count)
+	}
+}
+
+func TestBenchRate(t *testing.T) {
+	addr := startServer(t)
+
+	res := runClient(t, addr, "", "bench", "-rate", "100", "-duration", "300ms", "-seed", "5", "-o", "json")
+	if res.code != exitOK {
+		t.Fatalf("bench: exit %d: %s", res.code, res.stderr)
+	}
+
+	// 100/s for 300ms is 30 operations, allowing for timer slack.
+	report := decodeReport(t, res.stdout)
+	if n := report.Total.Count + report.Total.Errors; n < 10 || n > 35 {
+		t.Fatalf("expected about 30 paced operations, got %d", n)
+	}
+	if report.Rate != 100 {
+		t.Fatalf("expected the target rate in the report, got %v", report.Rate)
+	}
+}
+
+func TestBenchText(t *testing.T) {
+	addr := startServer(t)
+
+	// Deleting more than is created runs out of posts.
+	res := runClient(t, addr, "", "bench", "-requests", "20", "-concurrency", "1", "-seed", "2", "-mix", "delete=1")
+	if res.code != exitOK {
+		t.Fatalf("bench: exit %d: %s", res.code, res.stderr)
+	}
+	for _, want := range []string{"2 posts seeded", "P99.9", "delete", "total", "18 × delete: no posts left"} {
+		if !strings.Contains(res.stdout, want) {
+			t.Fatalf("expected %q in the report:\n%s", want, res.stdout)
+		}
+	}
+}
+
+func TestBenchUsage(t *testing.T) {
+	addr := startServer(t)
+
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-mix", "read=1,publish=1"}, `invalid -mix "read=1,publish=1"`},
+		{[]string{"-mix", "read=0"}, "weights add up to zero"},
+		{[]string{"-duration", "0"}, "one of -duration and -requests must be set"},
+		{[]string{"-concurrency", "0"}, "-concurrency must be at least 1"},
+		{[]string{"-rate", "-1"}, "-rate must be from 0 to 1000000"},
+		{[]string{"-rate", "2e9"}, "-rate must be from 0 to 1000000"},
+		{[]string{"-rate", "NaN"}, "-rate must be from 0 to 1000000"},
+		{[]string{"-o", "table"}, `unknown -output "table"`},
+	}
+	for _, tc := range cases {
+		res := runClient(t, addr, "", append([]string{"bench"}, tc.args...)...)
+		if res.code != exitUsage || !strings.Contains(res.stderr, tc.want) {
+			t.Fatalf("%v: expected exit %d with %q, got %d: %s", tc.args, exitUsage, tc.want, res.code, res.stderr)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/yaml.v3"
+
+	"grpc-blog/proto/blogpb"
+)
+
+// Archive formats.
+const (
+	archiveJSONL    = "jsonl"
+	archiveMarkdown = "markdown"
+)
+
+// export implements the "export" subcommand: it streams every post into
+// an archive, either JSON Lines or a directory of Markdown files with YAML
+// front matter. The stream has no deadline; -timeout does not apply.
+func (c *cli) export(ctx context.Context, args []string) error {
+	fs := c.flagSet("export", "[-format jsonl|markdown] [-out path]")
+	format := fs.String("format", archiveJSONL, "archive format: jsonl or markdown")
+	out := fs.String("out", "-", `JSON Lines file, "-" for stdout, or the directory of Markdown files`)
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	var (
+		w   archiveWriter
+		err error
+	)
+	switch *format {
+	case archiveJSONL:
+		w, err = newJSONLWriter(*out, c.stdout)
+	case archiveMarkdown:
+		if *out == "-" {
+			return usageErrorf("-format markdown needs -out <directory>")
+		}
+		w, err = newMarkdownWriter(*out)
+	default:
+		return usageErrorf("unknown -format %q; want jsonl or markdown", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	n := 0
+	for post, err := range c.blog.ExportPosts(ctx) {
+		if err == nil {
+			err = w.write(post)
+		}
+		if err != nil {
+			w.abort()
+			return err
+		}
+		n++
+	}
+	if err := w.close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stderr, "exported %d posts\n", n)
+	return nil
+}
+
+// archiveWriter writes posts to an archive. After an error the archive is
+// aborted, which leaves no partial JSON Lines file behind.
+type archiveWriter interface {
+	write(post *blogpb.BlogPost) error
+	close() error
+	abort()
+}
+
+// jsonlWriter writes one JSON post per line. A file is written under a
+// temporary name and renamed into place when complete.
+type jsonlWriter struct {
+	w    io.Writer
+	file *os.File // nil for stdout
+	path string
+}
+
+func newJSONLWriter(path string, stdout io.Writer) (*jsonlWriter, error) {
+	if path == "-" {
+		return &jsonlWriter{w: stdout}, nil
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, err
+	}
+	return &jsonlWriter{w: f, file: f, path: path}, nil
+}
+
+func (w *jsonlWriter) write(post *blogpb.BlogPost) error {
+	data, err := protojson.Marshal(post)
+	if err != nil {
+		return err
+	}
+	// protojson may add spaces; a line must be compact.
+	var line bytes.Buffer
+	if err := json.Compact(&line, data); err != nil {
+		return err
+	}
+	line.WriteByte('\n')
+	_, err = w.w.Write(line.Bytes())
+	return err
+}
+
+func (w *jsonlWriter) close() error {
+	if w.file == nil {
+		return nil
+	}
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.file.Name())
+		return err
+	}
+	return os.Rename(w.file.Name(), w.path)
+}
+
+func (w *jsonlWriter) abort() {
+	if w.file != nil {
+		w.file.Close()
+		os.Remove(w.file.Name())
+	}
+}
+
+// markdownWriter writes each post to <dir>/<name>.md, named after its slug
+// or else its title. A name already taken in this export gets the post ID
+// appended.
+type markdownWriter struct {
+	dir   string
+	names map[string]bool
+}
+
+func newMarkdownWriter(dir string) (*markdownWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &markdownWriter{dir: dir, names: map[string]bool{}}, nil
+}
+
+func (w *markdownWriter) write(post *blogpb.BlogPost) error {
+	name := post.Slug
+	if name == "" {
+		name = slugify(post.Title)
+	}
+	if name == "" || w.names[name] {
+		name = strings.TrimPrefix(name+"-"+post.PostId, "-")
+	}
+	w.names[name] = true
+
+	data, err := marshalMarkdown(post)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(w.dir, name+".md"), data, 0o644)
+}
+
+func (w *markdownWriter) close() error { return nil }
+
+func (w *markdownWriter) abort() {}
+
+// frontMatter is the header of a Markdown post: YAML when exported, YAML
+// or TOML when imported.
+type frontMatter struct {
+	ID     string     `yaml:"id" toml:"id"`
+	Title  string     `yaml:"title" toml:"title"`
+	Author string     `yaml:"author,omitempty" toml:"author"`
+	Date   *time.Time `yaml:"date,omitempty" toml:"date"`
+	Tags   []string   `yaml:"tags,flow,omitempty" toml:"tags"`
+	Slug   string     `yaml:"slug,omitempty" toml:"slug"`
+	// Format is the content format; Markdown when empty.
+	Format string `yaml:"format,omitempty" toml:"format"`
+}
+
+// marshalMarkdown renders post as YAML front matter between "---" lines,
+// followed by the content. The format is only written for content that is
+// not Markdown.
+func marshalMarkdown(post *blogpb.BlogPost) ([]byte, error) {
+	fm := frontMatter{ID: post.PostId, Title: post.Title, Author: post.Author, Tags: post.Tags, Slug: post.Slug}
+	if post.ContentFormat != blogpb.ContentFormat_CONTENT_FORMAT_MARKDOWN {
+		fm.Format = formatName(post.ContentFormat)
+	}
+	if post.PublicationDate != nil {
+		date := post.PublicationDate.AsTime()
+		fm.Date = &date
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(fm); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("---\n\n")
+	buf.WriteString(post.Content)
+	if post.Content != "" && !strings.HasSuffix(post.Content, "\n") {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// slugify turns a title into a file name: lower case letters and digits
+// separated by single dashes, at most 60 bytes.
+func slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			dash = true
+			continue
+		}
+		sep := ""
+		if dash && b.Len() > 0 {
+			sep = "-"
+		}
+		if b.Len()+len(sep)+utf8.RuneLen(r) > 60 {
+			break
+		}
+		b.WriteString(sep)
+		b.WriteRune(r)
+		dash = false
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExportJSONL(t *testing.T) {
+	addr := startServer(t)
+	for _, title := range []string{"One", "Two", "Three"} {
+		if res := runClient(t, addr, "", "posts", "create", "-title", title, "-tags", "a,b"); res.code != exitOK {
+			t.Fatalf("create: %s", res.stderr)
+		}
+	}
+
+	res := runClient(t, addr, "", "export")
+	if res.code != exitOK || !strings.Contains(res.stderr, "exported 3 posts") {
+		t.Fatalf("export: exit %d: %s", res.code, res.stderr)
+	}
+	lines := strings.Split(strings.TrimSuffix(res.stdout, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", res.stdout)
+	}
+	for _, line := range lines {
+		if post := decodePost(t, line); len(post.Tags) != 2 || post.PublicationDate == nil {
+			t.Fatalf("expected tags and date in %s", line)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "posts.jsonl")
+	if res := runClient(t, addr, "", "export", "-out", path); res.code != exitOK {
+		t.Fatalf("export to file: exit %d: %s", res.code, res.stderr)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != res.stdout {
+		t.Fatalf("expected the file to hold the same archive, got %q (%v)", data, err)
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	addr := startServer(t)
+	create := func(args ...string) string {
+		t.Helper()
+		res := runClient(t, addr, "", append([]string{"posts", "create"}, args...)...)
+		if res.code != exitOK {
+			t.Fatalf("create: %s", res.stderr)
+		}
+		return decodePost(t, res.stdout).PostId
+	}
+	id := create("-title", "Hello, World!", "-author", "ann", "-tags", "go,grpc",
+		"-date", "2024-05-01T09:00:00Z", "-content", "# Hi\n\nBody.")
+	create("-title", "hello world")
+	untitled := create("-title", "!!!")
+
+	dir := filepath.Join(t.TempDir(), "posts")
+	if res := runClient(t, addr, "", "export", "-format", "markdown", "-out", dir); res.code != exitOK {
+		t.Fatalf("export: exit %d: %s", res.code, res.stderr)
+	}
+
+	files := map[string]string{}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		files[e.Name()] = string(data)
+	}
+
+	// The titles collide, so one of them is named after its ID too.
+	if len(files) != 3 || files["hello-world.md"] == "" || files[untitled+".md"] == "" {
+		t.Fatalf("unexpected files %v", slices.Collect(maps.Keys(files)))
+	}
+
+	want := `---
+id: ` + id + `
+title: Hello, World!
+author: ann
+date: 2024-05-01T09:00:00Z
+tags: [go, grpc]
+format: plain
+---
+
+# Hi
+
+Body.
+`
+	for _, data := range files {
+		if strings.Contains(data, id) && data != want {
+			t.Fatalf("unexpected file:\n%s\nwant:\n%s", data, want)
+		}
+	}
+}
+
+func TestExportUsage(t *testing.T) {
+	addr := startServer(t)
+
+	res := runClient(t, addr, "", "export", "-format", "markdown")
+	if res.code != exitUsage || !strings.Contains(res.stderr, "needs -out") {
+		t.Fatalf("expected a usage error, got exit %d: %s", res.code, res.stderr)
+	}
+	res = runClient(t, addr, "", "export", "-format", "csv")
+	if res.code != exitUsage || !strings.Contains(res.stderr, `unknown -format "csv"`) {
+		t.Fatalf("expected a usage error, got exit %d: %s", res.code, res.stderr)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Hello, World!":                  "hello-world",
+		"  Go 1.24 -- released":          "go-1-24-released",
+		"Ünïcode títle":                  "ünïcode-títle",
+		"!!!":                            "",
+		strings.Repeat("abcdefghi ", 10): "abcdefghi-abcdefghi-abcdefghi-abcdefghi-abcdefghi-abcdefghi",
+	}
+	for in, want := range cases {
+		if got := slugify(in); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+
+	"grpc-blog/proto/blogpb"
+)
+
+// maxDiffCells bounds the work of a content diff: the product of the line
+// counts of both versions.
+const maxDiffCells = 1 << 20
+
+// importPosts implements the "import" subcommand: it syncs a directory of
+// Markdown files to the server. Files are keyed by slug, from the front
+// matter or else the file name; a file with an id in its front matter, as
+// export writes, matches that post first.
+//
+// Posts without a file are created and changed posts updated; with -delete,
+// posts that have a slug but no file are deleted. -dry-run prints the
+// changes without making them. -timeout applies to each RPC.
+func (c *cli) importPosts(ctx context.Context, args []string) error {
+	fset := c.flagSet("import", "[-dry-run] [-delete] <dir>")
+	dryRun := fset.Bool("dry-run", false, "print the changes as a diff without making them")
+	prune := fset.Bool("delete", false, "delete posts that have a slug but no file")
+	if err := c.parse(fset, args, 1); err != nil {
+		return err
+	}
+
+	files, err := readMarkdownDir(fset.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var current []*blogpb.BlogPost
+	for post, err := range c.blog.ExportPosts(ctx) {
+		if err != nil {
+			return err
+		}
+		current = append(current, post)
+	}
+
+	plan, err := planImport(files, current, *prune)
+	if err != nil {
+		return err
+	}
+
+	for _, ch := range plan.changes {
+		ch.print(c, *dryRun)
+		if *dryRun {
+			continue
+		}
+		if err := c.apply(ctx, ch); err != nil {
+			return fmt.Errorf("%s: %w", ch.name(), err)
+		}
+	}
+
+	counts := map[string]int{}
+	for _, ch := range plan.changes {
+		counts[ch.action]++
+	}
+	summary := fmt.Sprintf("%d created, %d updated, %d deleted, %d unchanged",
+		counts["create"], counts["update"], counts["delete"], plan.unchanged)
+	if *dryRun {
+		summary += " (dry run)"
+	}
+	fmt.Fprintln(c.stdout, summary)
+	return nil
+}
+
+// markdownFile is a post read from a Markdown file.
+type markdownFile struct {
+	path string // relative to the directory
+	id   string // from the front matter, if any
+	post *blogpb.BlogPost
+}
+
+// readMarkdownDir reads every .md and .markdown file under dir, skipping
+// hidden files and directories, and reports files sharing a slug.
+func readMarkdownDir(dir string) ([]markdownFile, error) {
+	var files []markdownFile
+	slugs := map[string]string{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || (filepath.Ext(path) != ".md" && filepath.Ext(path) != ".markdown") {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		file, err := parseMarkdown(data, stem)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		file.path = rel
+
+		if other, ok := slugs[file.post.Slug]; ok {
+			return fmt.Errorf("%s and %s have the same slug %q", other, rel, file.post.Slug)
+		}
+		slugs[file.post.Slug] = rel
+		files = append(files, file)
+		return nil
+	})
+	return files, err
+}
+
+// parseMarkdown reads a post from Markdown with optional front matter:
+// YAML between "---" lines or TOML between "+++" lines. The title and slug
+// default to the file name stem, and the format to Markdown.
+func parseMarkdown(data []byte, stem string) (markdownFile, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	var fm frontMatter
+	for _, delim := range []string{"---", "+++"} {
+		rest, ok := strings.CutPrefix(text, delim+"\n")
+		if !ok {
+			continue
+		}
+		head, body, ok := strings.Cut("\n"+rest+"\n", "\n"+delim+"\n")
+		if !ok {
+			return markdownFile{}, fmt.Errorf("front matter has no closing %q", delim)
+		}
+
+		var err error
+		if delim == "---" {
+			err = yaml.Unmarshal([]byte(head), &fm)
+		} else {
+			_, err = toml.Decode(head, &fm)
+		}
+		if err != nil {
+			return markdownFile{}, fmt.Errorf("front matter: %w", err)
+		}
+		text = body
+		break
+	}
+
+	format, ok := contentFormats[cmp.Or(fm.Format, "markdown")]
+	if !ok {
+		return markdownFile{}, fmt.Errorf("unknown format %q: want plain, markdown or html", fm.Format)
+	}
+	post := &blogpb.BlogPost{
+		Title:         cmp.Or(fm.Title, stem),
+		Author:        fm.Author,
+		Tags:          fm.Tags,
+		Slug:          cmp.Or(fm.Slug, slugify(stem)),
+		ContentFormat: format,
+		Content:       strings.Trim(text, "\n"),
+	}
+	if post.Slug == "" {
+		return markdownFile{}, errors.New("no slug in the front matter or the file name")
+	}
+	if fm.Date != nil {
+		post.PublicationDate = timestamppb.New(*fm.Date)
+	}
+	return markdownFile{id: fm.ID, post: post}, nil
+}
+
+// importPlan is what an import changes.
+type importPlan struct {
+	changes   []change
+	unchanged int
+}
+
+// change is one post to create, update or delete.
+type change struct {
+	action string
+	file   string           // empty for deletes
+	post   *blogpb.BlogPost // as it should be; as it is for deletes
+	old    *blogpb.BlogPost // for updates
+	fields []string         // changed by an update
+}
+
+// planImport matches files to the current posts. Deletes come first, then
+// updates, then creates, so a slug freed by one change can be taken by a
+// later one.
+func planImport(files []markdownFile, current []*blogpb.BlogPost, prune bool) (importPlan, error) {
+	byID := map[string]*blogpb.BlogPost{}
+	bySlug := map[string]*blogpb.BlogPost{}
+	for _, post := range current {
+		byID[post.PostId] = post
+		if post.Slug != "" {
+			bySlug[post.Slug] = post
+		}
+	}
+
+	var (
+		plan            importPlan
+		updates, create []change
+		matched         = map[string]string{} // post ID to file
+	)
+	for _, f := range files {
+		post := byID[f.id]
+		if post == nil {
+			post = bySlug[f.post.Slug]
+		}
+		if post == nil {
+			if f.post.PublicationDate == nil {
+				f.post.PublicationDate = timestamppb.Now()
+			}
+			create = append(create, change{action: "create", file: f.path, post: f.post})
+			continue
+		}
+
+		if other, ok := matched[post.PostId]; ok {
+			return plan, fmt.Errorf("%s and %s both match post %s", other, f.path, post.PostId)
+		}
+		matched[post.PostId] = f.path
+
+		f.post.PostId = post.PostId
+		if fields := changedFields(post, f.post); len(fields) > 0 {
+			updates = append(updates, change{action: "update", file: f.path, post: f.post, old: post, fields: fields})
+		} else {
+			plan.unchanged++
+		}
+	}
+
+	if prune {
+		for _, post := range current {
+			if _, ok := matched[post.PostId]; !ok && post.Slug != "" {
+				plan.changes = append(plan.changes, change{action: "delete", post: post})
+			}
+		}
+		slices.SortFunc(plan.changes, func(a, b change) int { return strings.Compare(a.post.Slug, b.post.Slug) })
+	}
+	plan.changes = append(append(plan.changes, updates...), create...)
+	return plan, nil
+}
+
+// changedFields lists the fields of want that differ from post. A file
+// without a date keeps the date of its post.
+func changedFields(post, want *blogpb.BlogPost) []string {
+	var fields []string
+	if post.Title != want.Title {
+		fields = append(fields, "title")
+	}
+	if post.Author != want.Author {
+		fields = append(fields, "author")
+	}
+	if want.PublicationDate != nil && (post.PublicationDate == nil || !post.PublicationDate.AsTime().Equal(want.PublicationDate.AsTime())) {
+		fields = append(fields, "date")
+	}
+	if !slices.Equal(post.Tags, want.Tags) {
+		fields = append(fields, "tags")
+	}
+	if post.Slug != want.Slug {
+		fields = append(fields, "slug")
+	}
+	if formatName(post.ContentFormat) != formatName(want.ContentFormat) {
+		fields = append(fields, "format")
+	}
+	if strings.Trim(post.Content, "\n") != want.Content {
+		fields = append(fields, "content")
+	}
+	return fields
+}
+
+func (ch change) name() string {
+	if ch.file != "" {
+		return ch.file
+	}
+	return ch.post.Slug
+}
+
+// print describes ch and, for a dry run, diffs what it changes.
+func (ch change) print(c *cli, diff bool) {
+	switch ch.action {
+	case "create":
+		fmt.Fprintf(c.stdout, "create %s (slug %s)\n", ch.file, ch.post.Slug)
+	case "update":
+		fmt.Fprintf(c.stdout, "update %s (post %s): %s\n", ch.file, ch.post.PostId, strings.Join(ch.fields, ", "))
+	case "delete":
+		fmt.Fprintf(c.stdout, "delete %s (post %s)\n", ch.post.Slug, ch.post.PostId)
+	}
+	if !diff || ch.action != "update" {
+		return
+	}
+
+	for _, field := range ch.fields {
+		var was, now string
+		switch field {
+		case "title":
+			was, now = ch.old.Title, ch.post.Title
+		case "author":
+			was, now = ch.old.Author, ch.post.Author
+		case "date":
+			was, now = formatDate(ch.old), formatDate(ch.post)
+		case "tags":
+			was, now = strings.Join(ch.old.Tags, ", "), strings.Join(ch.post.Tags, ", ")
+		case "slug":
+			was, now = ch.old.Slug, ch.post.Slug
+		case "format":
+			was, now = formatName(ch.old.ContentFormat), formatName(ch.post.ContentFormat)
+		case "content":
+			fmt.Fprintln(c.stdout, "  content:")
+			for _, line := range diffLines(strings.Trim(ch.old.Content, "\n"), ch.post.Content) {
+				fmt.Fprintf(c.stdout, "    %s\n", line)
+			}
+			continue
+		}
+		fmt.Fprintf(c.stdout, "  - %s: %s\n  + %s: %s\n", field, was, field, now)
+	}
+}
+
+// apply makes ch on the server.
+func (c *cli) apply(ctx context.Context, ch change) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	switch ch.action {
+	case "create":
+		_, err := c.blog.CreatePost(ctx, ch.post)
+		return err
+	case "update":
+		_, err := c.blog.UpdatePost(ctx, ch.post)
+		return err
+	case "delete":
+		return c.blog.DeletePost(ctx, ch.post.PostId)
+	}
+	return fmt.Errorf("unknown change %q", ch.action)
+}
+
+// diffLines returns the lines removed from a, marked "-", and added in b,
+// marked "+", in order. Lines both have in common are left out.
+func diffLines(a, b string) []string {
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+	if len(x)*len(y) > maxDiffCells {
+		return []string{fmt.Sprintf("- (%d lines)", len(x)), fmt.Sprintf("+ (%d lines)", len(y))}
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i++
+			j++
+		case j == len(y) || i < len(x) && lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+x[i])
+			i++
+		default:
+			out = append(out, "+ "+y[j])
+			j++
+		}
+	}
+	return out
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"grpc-blog/proto/blogpb"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func TestParseMarkdown(t *testing.T) {
+	yamlFile := "---\ntitle: Hello\nauthor: ann\ndate: 2024-05-01\ntags: [go, grpc]\nslug: hi\ndraft: true\n---\n\n# Hello\n\nBody.\n"
+	f, err := parseMarkdown([]byte(yamlFile), "file")
+	if err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	if p := f.post; p.Title != "Hello" || p.Author != "ann" || p.Slug != "hi" || !slices.Equal(p.Tags, []string{"go", "grpc"}) ||
+		p.ContentFormat != blogpb.ContentFormat_CONTENT_FORMAT_MARKDOWN ||
+		p.Content != "# Hello\n\nBody." || formatDate(p) != "2024-05-01T00:00:00Z" {
+		t.Fatalf("unexpected post from YAML: %v", p)
+	}
+
+	tomlFile := "+++\r\ntitle = \"Tips\"\r\ntags = [\"go\"]\r\ndate = 2024-06-01T10:00:00Z\r\nformat = \"html\"\r\n+++\r\nSome tips.\r\n"
+	f, err = parseMarkdown([]byte(tomlFile), "Go Tips")
+	if err != nil {
+		t.Fatalf("toml: %v", err)
+	}
+	if p := f.post; p.Title != "Tips" || p.Slug != "go-tips" || p.Content != "Some tips." || formatDate(p) != "2024-06-01T10:00:00Z" ||
+		p.ContentFormat != blogpb.ContentFormat_CONTENT_FORMAT_HTML {
+		t.Fatalf("unexpected post from TOML: %v", p)
+	}
+
+	f, err = parseMarkdown([]byte("Just text.\n"), "Note")
+	if err != nil || f.post.Title != "Note" || f.post.Slug != "note" || f.post.Content != "Just text." {
+		t.Fatalf("unexpected post without front matter: %v, %v", f.post, err)
+	}
+
+	for _, bad := range []string{"---\ntitle: x\n", "---\ntitle: [\n---\n", "+++\ntitle = \n+++\n", "---\nformat: rst\n---\n"} {
+		if _, err := parseMarkdown([]byte(bad), "x"); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+	if _, err := parseMarkdown([]byte("text"), "!!!"); err == nil {
+		t.Error("expected an error without a slug")
+	}
+}
+
+func TestImportSync(t *testing.T) {
+	addr := startServer(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"hello.md":      "---\ntitle: Hello\ntags: [a]\n---\nOne\nTwo\n",
+		"drafts/tip.md": "+++\ntitle = \"Tip\"\nslug = \"go-tip\"\n+++\nTip.\n",
+		"gone.md":       "Soon removed.\n",
+		".hidden/x.md":  "Skipped.\n",
+		"notes.txt":     "Skipped.\n",
+	})
+	// Posts without a slug are never deleted.
+	runClient(t, addr, "", "posts", "create", "-title", "Written elsewhere")
+
+	res := runClient(t, addr, "", "import", "-dry-run", dir)
+	if res.code != exitOK || !strings.Contains(res.stdout, "3 created, 0 updated, 0 deleted, 0 unchanged (dry run)") {
+		t.Fatalf("dry run: exit %d: %s%s", res.code, res.stdout, res.stderr)
+	}
+	if res := runClient(t, addr, "", "posts", "list", "-q"); strings.Count(res.stdout, "\n") != 1 {
+		t.Fatalf("expected the dry run to change nothing, got posts %q", res.stdout)
+	}
+
+	res = runClient(t, addr, "", "import", dir)
+	if res.code != exitOK || !strings.Contains(res.stdout, "create drafts/tip.md (slug go-tip)") {
+		t.Fatalf("import: exit %d: %s%s", res.code, res.stdout, res.stderr)
+	}
+
+	writeFiles(t, dir, map[string]string{"hello.md": "---\ntitle: Hello\ntags: [a, b]\n---\nOne\nThree\n"})
+	os.Remove(filepath.Join(dir, "gone.md"))
+
+	res = runClient(t, addr, "", "import", "-dry-run", "-delete", dir)
+	for _, want := range []string{
+		"delete gone (post ",
+		"update hello.md (post ",
+		"  - tags: a\n  + tags: a, b\n",
+		"  content:\n    - Two\n    + Three\n",
+		"0 created, 1 updated, 1 deleted, 1 unchanged (dry run)",
+	} {
+		if !strings.Contains(res.stdout, want) {
+			t.Fatalf("expected %q in the dry run:\n%s", want, res.stdout)
+		}
+	}
+
+	if res := runClient(t, addr, "", "import", "-delete", dir); res.code != exitOK {
+		t.Fatalf("import: exit %d: %s", res.code, res.stderr)
+	}
+	res = runClient(t, addr, "", "posts", "list", "-o", "table", "-columns", "slug,title,tags")
+	for _, want := range []string{"hello   Hello", "a,b", "go-tip", "Written elsewhere"} {
+		if !strings.Contains(res.stdout, want) {
+			t.Fatalf("expected %q in the posts:\n%s", want, res.stdout)
+		}
+	}
+	if strings.Contains(res.stdout, "gone") {
+		t.Fatalf("expected gone to be deleted:\n%s", res.stdout)
+	}
+
+	res = runClient(t, addr, "", "import", "-delete", dir)
+	if !strings.Contains(res.stdout, "0 created, 0 updated, 0 deleted, 2 unchanged") {
+		t.Fatalf("expected a second import to change nothing, got %s", res.stdout)
+	}
+}
+
+func TestImportExported(t *testing.T) {
+	addr := startServer(t)
+	runClient(t, addr, "", "posts", "create", "-title", "No slug", "-content", "x")
+
+	dir := t.TempDir()
+	if res := runClient(t, addr, "", "export", "-format", "markdown", "-out", dir); res.code != exitOK {
+		t.Fatalf("export: %s", res.stderr)
+	}
+
+	// The file matches its post by id, which takes the file's slug.
+	res := runClient(t, addr, "", "import", dir)
+	if res.code != exitOK || !strings.Contains(res.stdout, "0 created, 1 updated") || !strings.Contains(res.stdout, ": slug\n") {
+		t.Fatalf("import: exit %d: %s%s", res.code, res.stdout, res.stderr)
+	}
+}
+
+func TestImportDates(t *testing.T) {
+	addr := startServer(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"hello.md": "---\ntitle: Hello\ndate: 2024-05-01\n---\nOne\n"})
+	if res := runClient(t, addr, "", "import", dir); res.code != exitOK {
+		t.Fatalf("import: exit %d: %s", res.code, res.stderr)
+	}
+
+	// Updating other fields keeps the date.
+	writeFiles(t, dir, map[string]string{"hello.md": "---\ntitle: Hello again\ndate: 2024-05-01\n---\nTwo\n"})
+	res := runClient(t, addr, "", "import", dir)
+	if !strings.Contains(res.stdout, ": title, content\n") {
+		t.Fatalf("expected title and content to change, got %s%s", res.stdout, res.stderr)
+	}
+
+	out := t.TempDir()
+	if res := runClient(t, addr, "", "export", "-format", "markdown", "-out", out); res.code != exitOK {
+		t.Fatalf("export: %s", res.stderr)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "hello.md"))
+	if err != nil || !strings.Contains(string(data), "date: 2024-05-01T00:00:00Z") {
+		t.Fatalf("expected the exported post to keep its date, got %q, %v", data, err)
+	}
+
+	// A changed date is updated, and a missing one leaves the date alone.
+	writeFiles(t, dir, map[string]string{"hello.md": "---\ntitle: Hello again\ndate: 2024-06-01\n---\nTwo\n"})
+	res = runClient(t, addr, "", "import", "-dry-run", dir)
+	if !strings.Contains(res.stdout, "  - date: 2024-05-01T00:00:00Z\n  + date: 2024-06-01T00:00:00Z\n") {
+		t.Fatalf("expected a date diff, got %s%s", res.stdout, res.stderr)
+	}
+	runClient(t, addr, "", "import", dir)
+	writeFiles(t, dir, map[string]string{"hello.md": "---\ntitle: Hello again\n---\nTwo\n"})
+	if res := runClient(t, addr, "", "import", dir); !strings.Contains(res.stdout, "1 unchanged") {
+		t.Fatalf("expected a file without a date to change nothing, got %s", res.stdout)
+	}
+	res = runClient(t, addr, "", "posts", "list", "-o", "table", "-columns", "date")
+	if !strings.Contains(res.stdout, "2024-06-01") {
+		t.Fatalf("expected the new date, got %s", res.stdout)
+	}
+}
+
+func TestImportDuplicateSlugs(t *testing.T) {
+	addr := startServer(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.md": "---\nslug: same\n---\n",
+		"b.md": "---\nslug: same\n---\n",
+	})
+
+	res := runClient(t, addr, "", "import", dir)
+	if res.code != exitError || !strings.Contains(res.stderr, `a.md and b.md have the same slug "same"`) {
+		t.Fatalf("expected a duplicate slug error, got exit %d: %s", res.code, res.stderr)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := diffLines("a\nb\nc\nd", "a\nc\nx\nd\ne")
+	want := []string{"- b", "+ x", "+ e"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("diffLines = %q, want %q", got, want)
+	}
+}
@@ -2,128 +2,206 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
-	"log"
+	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
-	"go.uber.org/zap"
-
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"grpc-blog/blogclient"
 	"grpc-blog/internal/infra/tracing"
-	"grpc-blog/proto/blogpb"
+)
+
+const usage = `usage: client [flags] <command> [args]
+
+commands:
+  posts    create, get, list, update and delete blog posts
+  reflect  discover and call RPCs through server reflection
+  shell    interactive prompt over one connection
+  bench    measure throughput and latency under load
+  export   write every post to a JSON Lines or Markdown archive
+  import   sync a directory of Markdown files to the server
+  backup   create, list and restore server-side backups
+
+Run "client <command> -h" for help on a command.
+
+flags:`
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
 )
 
 func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// cli holds what every command needs: the connection, the per-command
+// timeout and standard streams.
+type cli struct {
+	conn    *grpc.ClientConn
+	blog    *blogclient.Client
+	timeout time.Duration
+	// adminAddr and adminToken reach the admin listener, for backup.
+	adminAddr  string
+	adminToken string
+	stdin      io.Reader
+	stdout     io.Writer
+	stderr     io.Writer
+
+	// recent remembers post IDs seen in the shell; nil otherwise.
+	recent *recentIDs
+}
+
+// run executes the command line args and returns the exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("client", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", envOr("BLOG_ADDR", "localhost:50051"), "server address (env BLOG_ADDR)")
+	adminAddr := fs.String("admin-addr", envOr("BLOG_ADMIN_ADDR", "localhost:9091"), "server admin address for backup, authenticated with env BLOG_ADMIN_TOKEN (env BLOG_ADMIN_ADDR)")
+	timeout := fs.Duration("timeout", 5*time.Second, "deadline for each command")
+	retries := fs.Int("retries", blogclient.DefaultServiceConfig().Retry.MaxAttempts, "attempts for reads failing with UNAVAILABLE, 1 to disable retries")
+	hedgeDelay := fs.Duration("hedge-delay", 0, "hedge reads instead of retrying: send another attempt after this delay, up to -retries")
+	exporter := fs.String("trace-exporter", tracing.ExporterNone, "span exporter (stdout, otlp-grpc, otlp-http, none)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if *retries < 1 {
+		fmt.Fprintln(stderr, "-retries must be at least 1")
+		return exitUsage
+	}
+
 	// ---- tracing ----
-	shutdown, err := tracing.InitTracer()
+	traceCfg := tracing.DefaultConfig("grpc-blog-client")
+	traceCfg.Exporter = *exporter
+	shutdown, err := tracing.InitTracer(traceCfg)
 	if err != nil {
-		log.Fatalf("failed to init tracer: %v", err)
+		fmt.Fprintf(stderr, "error: init tracer: %v\n", err)
+		return exitError
 	}
 	defer shutdown(context.Background())
 
-	// ---- logger ----
-	logger, _ := zap.NewProduction()
-	defer logger.Sync()
-
-	// ---- context ----
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
 	// ---- grpc client ----
-	conn, err := grpc.NewClient(
-		"localhost:50051",
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	// -timeout bounds each command, so methods get no timeouts of their own.
+	sc := blogclient.DefaultServiceConfig()
+	sc.Timeouts = nil
+	sc.Retry.MaxAttempts = *retries
+	if *hedgeDelay > 0 {
+		sc.Hedging = &blogclient.HedgingPolicy{
+			MaxAttempts:   *retries,
+			Delay:         *hedgeDelay,
+			NonFatalCodes: []codes.Code{codes.Unavailable},
+		}
+	}
+
+	dialOpts := blogclient.DialOptions(blogclient.WithServiceConfig(sc))
+	conn, err := grpc.NewClient(*addr, append(dialOpts, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))...)
 	if err != nil {
-		logger.Fatal("failed to create grpc client", zap.Error(err))
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
 	}
 	defer conn.Close()
 
-	client := blogpb.NewBlogServiceClient(conn)
-
-	// Define the flags. Each function takes the flag name, default value, and a help message.
-	opType := flag.String("type", "fetch", "the type of operation")
-	postID := flag.String("id", "", "the id to fetch")
-
-	// Parse the command line arguments
-	flag.Parse()
-
-	switch *opType {
-	case "create":
-		// ---- call API ----
-		resp, err := client.CreatePost(ctx, &blogpb.CreatePostRequest{
-			Title:           "Create Post",
-			Content:         "First Post",
-			Author:          "Siddhant",
-			PublicationDate: timestamppb.New(time.Now()),
-			Tags:            []string{"create_post"},
-		})
-		if err != nil {
-			logger.Fatal("CreatePost failed", zap.Error(err))
-		}
+	c := &cli{
+		conn:    conn,
+		blog:    blogclient.NewFromConn(conn, blogclient.WithTimeout(0)), // dispatch sets deadlines
+		timeout: *timeout,
+		stdin:   stdin,
+		stdout:  stdout,
+		stderr:  stderr,
 
-		logger.Info("post created",
-			zap.String("post_id", resp.Post[0].PostId),
-			zap.String("title", resp.Post[0].Title),
-		)
-
-	case "fetch":
-		// ---- call API --i--
-		resp, err := client.ReadPost(ctx, &blogpb.ReadPostRequest{
-			PostId: *postID,
-		})
-		if err != nil {
-			logger.Fatal("FetchPost failed", zap.Error(err))
-		}
+		adminAddr:  *adminAddr,
+		adminToken: os.Getenv("BLOG_ADMIN_TOKEN"),
+	}
+	// These run for longer than -timeout; it bounds their steps, if any.
+	switch fs.Arg(0) {
+	case "shell":
+		return c.exit(c.shell(ctx, fs.Args()[1:]))
+	case "bench":
+		return c.exit(c.bench(ctx, fs.Args()[1:]))
+	case "export":
+		return c.exit(c.export(ctx, fs.Args()[1:]))
+	case "import":
+		return c.exit(c.importPosts(ctx, fs.Args()[1:]))
+	}
+	return c.exit(c.dispatch(ctx, fs.Args()))
+}
+
+// dispatch runs one command under the timeout.
+func (c *cli) dispatch(ctx context.Context, args []string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
 
-		logger.Info("post fetched",
-			zap.String("post_id", resp.Post[0].PostId),
-			zap.String("title", resp.Post[0].Title),
-		)
+	command, rest := args[0], args[1:]
+	switch command {
+	case "posts":
+		return c.posts(ctx, rest)
+	case "reflect":
+		return c.reflect(ctx, rest)
+	case "backup":
+		return c.backup(ctx, rest)
+	default:
+		return usageErrorf("unknown command %q\n\n%s", command, strings.TrimSuffix(usage, "\n\nflags:"))
+	}
+}
 
-	case "fetchall":
-		// ---- call API --i--
-		resp, err := client.ReadAll(ctx, &blogpb.ReadAllRequest{})
-		if err != nil {
-			logger.Fatal("FetchPost failed", zap.Error(err))
+// exit reports err on stderr and maps it to an exit code.
+func (c *cli) exit(err error) int {
+	var uerr *usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &uerr):
+		if uerr.msg != "" {
+			fmt.Fprintln(c.stderr, uerr.msg)
 		}
+		return exitUsage
+	}
 
-		for _, post := range resp.Post {
-			logger.Info("post fetched",
-				zap.String("post_id", post.PostId),
-				zap.String("title", post.Title),
-			)
-		}
+	if st, ok := status.FromError(err); ok {
+		fmt.Fprintf(c.stderr, "error: %s: %s\n", st.Code(), st.Message())
+	} else {
+		fmt.Fprintf(c.stderr, "error: %v\n", err)
+	}
+	return exitError
+}
 
-	case "update":
+// usageError is a malformed command line; it exits with exitUsage. An
+// empty msg means the usage has already been printed.
+type usageError struct{ msg string }
 
-		// ---- call API ----
-		resp, err := client.UpdatePost(ctx, &blogpb.UpdatePostRequest{
-			PostId: *postID,
-			Title:  "updated title",
-		})
-		if err != nil {
-			logger.Fatal("FetchPost failed", zap.Error(err))
-		}
+func (e *usageError) Error() string { return e.msg }
 
-		logger.Info("post updated",
-			zap.String("post_id", resp.Post[0].PostId),
-			zap.String("title", resp.Post[0].Title),
-		)
-
-	case "delete":
-		_, err = client.DeletePost(ctx, &blogpb.DeletePostRequest{
-			PostId: *postID,
-		})
-		if err != nil {
-			logger.Fatal("FetchPost failed", zap.Error(err))
-		}
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
 
-		logger.Info("post deleted",
-			zap.String("post_id", *postID),
-		)
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
 	}
+	return fallback
 }
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"grpc-blog/internal/app/backup"
+	"grpc-blog/internal/app/blog"
+	grpctransport "grpc-blog/internal/transport/grpc"
+	httptransport "grpc-blog/internal/transport/http"
+	"grpc-blog/proto/blogpb"
+)
+
+// testAdminToken guards the admin listener of startServers.
+const testAdminToken = "test-token"
+
+// startServer serves BlogService and reflection on a loopback port.
+func startServer(t *testing.T) string {
+	addr, _ := startServers(t)
+	return addr
+}
+
+// startServers serves BlogService and reflection on a loopback port and,
+// like the server's admin listener, AdminService behind testAdminToken on
+// another.
+func startServers(t *testing.T) (addr, adminAddr string) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	srv := grpc.NewServer()
+	logger := zaptest.NewLogger(t)
+	service := blog.NewService(logger, blog.NewMemoryStore())
+	blogpb.RegisterBlogServiceServer(srv, grpctransport.NewBlogGRPCServer(service))
+	reflection.Register(srv)
+
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	adminSrv := grpc.NewServer()
+	backups := backup.NewManager(backup.Config{Dir: t.TempDir()}, logger, service)
+	blogpb.RegisterAdminServiceServer(adminSrv, grpctransport.NewAdminGRPCServer(backups))
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	admin := httptest.NewUnstartedServer(httptransport.RequireToken(testAdminToken, httptransport.SplitGRPC(adminSrv, http.NotFoundHandler())))
+	admin.Config.Protocols = protocols
+	admin.Start()
+	t.Cleanup(admin.Close)
+
+	return lis.Addr().String(), strings.TrimPrefix(admin.URL, "http://")
+}
+
+type result struct {
+	code           int
+	stdout, stderr string
+}
+
+func runClient(t *testing.T, addr, stdin string, args ...string) result {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), append([]string{"-addr", addr}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	return result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+func decodePost(t *testing.T, out string) *blogpb.BlogPost {
+	t.Helper()
+
+	post := new(blogpb.BlogPost)
+	if err := protojson.Unmarshal([]byte(out), post); err != nil {
+		t.Fatalf("output is not a post: %v\n%s", err, out)
+	}
+	return post
+}
+
+func TestPostsLifecycle(t *testing.T) {
+	addr := startServer(t)
+
+	res := runClient(t, addr, "", "posts", "create",
+		"-title", "Hello", "-content", "body", "-author", "ann",
+		"-tags", "go, grpc", "-date", "2024-05-01T09:00:00Z")
+	if res.code != exitOK {
+		t.Fatalf("create: exit %d: %s", res.code, res.stderr)
+	}
+	created := decodePost(t, res.stdout)
+	if created.Title != "Hello" || len(created.Tags) != 2 || created.Tags[1] != "grpc" {
+		t.Fatalf("unexpected post: %v", created)
+	}
+	if got := created.PublicationDate.AsTime().Format("2006-01-02"); got != "2024-05-01" {
+		t.Fatalf("expected the given date, got %s", got)
+	}
+
+	// Flags may follow the id; fields not given are kept.
+	res = runClient(t, addr, "", "posts", "update", created.PostId, "-title", "Renamed")
+	if res.code != exitOK {
+		t.Fatalf("update: exit %d: %s", res.code, res.stderr)
+	}
+	updated := decodePost(t, res.stdout)
+	if updated.Title != "Renamed" || updated.Content != "body" || updated.Author != "ann" || len(updated.Tags) != 2 ||
+		!updated.PublicationDate.AsTime().Equal(created.PublicationDate.AsTime()) {
+		t.Fatalf("expected only the title to change, got %v", updated)
+	}
+
+	// The date can be changed, from a flag or a file.
+	res = runClient(t, addr, "", "posts", "update", created.PostId, "-date", "2024-06-01T00:00:00Z")
+	if got := decodePost(t, res.stdout).PublicationDate.AsTime().Format("2006-01-02"); got != "2024-06-01" {
+		t.Fatalf("expected -date to change the date, got %s: %s", got, res.stderr)
+	}
+	res = runClient(t, addr, `{"publicationDate":"2024-07-01T00:00:00Z"}`, "posts", "update", created.PostId, "-file", "-")
+	if got := decodePost(t, res.stdout); got.PublicationDate.AsTime().Format("2006-01-02") != "2024-07-01" || got.Title != "Renamed" {
+		t.Fatalf("expected -file to change only the date, got %v: %s", got, res.stderr)
+	}
+
+	res = runClient(t, addr, "", "posts", "get", created.PostId)
+	if res.code != exitOK || decodePost(t, res.stdout).Title != "Renamed" {
+		t.Fatalf("get: exit %d: %s%s", res.code, res.stdout, res.stderr)
+	}
+
+	res = runClient(t, addr, "", "posts", "delete", created.PostId)
+	if res.code != exitOK || !strings.Contains(res.stdout, "deleted "+created.PostId) {
+		t.Fatalf("delete: exit %d: %s%s", res.code, res.stdout, res.stderr)
+	}
+
+	res = runClient(t, addr, "", "posts", "get", created.PostId)
+	if res.code != exitError || !strings.Contains(res.stderr, "error: post not found") {
+		t.Fatalf("expected a readable not found error, got exit %d: %s", res.code, res.stderr)
+	}
+}
+
+func TestCreateFromStdin(t *testing.T) {
+	addr := startServer(t)
+
+	in := `{"postId": "ignored", "title": "From file", "author": "bob", "tags": ["a"]}`
+	res := runClient(t, addr, in, "posts", "create", "-file", "-", "-author", "carol")
+	if res.code != exitOK {
+		t.Fatalf("create: exit %d: %s", res.code, res.stderr)
+	}
+
+	post := decodePost(t, res.stdout)
+	if post.Title != "From file" || post.Author != "carol" || post.PostId == "ignored" {
+		t.Fatalf("expected file fields with flags on top, got %v", post)
+	}
+	if post.PublicationDate == nil {
+		t.Fatal("expected the publication date to default to now")
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	addr := startServer(t)
+
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no command", nil, "usage: client"},
+		{"unknown command", []string{"bogus"}, `unknown command "bogus"`},
+		{"unknown posts command", []string{"posts", "publish"}, `unknown posts command "publish"`},
+		{"missing id", []string{"posts", "get"}, "usage: client posts get <id>"},
+		{"bad date", []string{"posts", "create", "-date", "yesterday"}, `invalid -date "yesterday"`},
+		{"empty update", []string{"posts", "update", "some-id"}, "no fields given"},
+		{"no attempts", []string{"-retries", "0", "posts", "list"}, "-retries must be at least 1"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := runClient(t, addr, "", tc.args...)
+			if res.code != exitUsage {
+				t.Fatalf("expected exit %d, got %d", exitUsage, res.code)
+			}
+			if !strings.Contains(res.stderr, tc.want) {
+				t.Fatalf("expected %q in stderr, got %q", tc.want, res.stderr)
+			}
+		})
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	res := runClient(t, addr, "", "-timeout", "1s", "posts", "list")
+	if res.code != exitError || !strings.HasPrefix(res.stderr, "error: Unavailable:") {
+		t.Fatalf("expected an Unavailable error, got exit %d: %s", res.code, res.stderr)
+	}
+}
+
+func TestReflectList(t *testing.T) {
+	addr := startServer(t)
+
+	res := runClient(t, addr, "", "reflect", "list")
+	if res.code != exitOK || !strings.Contains(res.stdout, "blog.BlogService") {
+		t.Fatalf("reflect list: exit %d: %s%s", res.code, res.stdout, res.stderr)
+	}
+}
+
+func TestContentFormat(t *testing.T) {
+	addr := startServer(t)
+
+	res := runClient(t, addr, "", "posts", "create", "-content", "# Hi", "-content-format", "markdown")
+	if res.code != exitOK {
+		t.Fatalf("create: exit %d: %s", res.code, res.stderr)
+	}
+	post := decodePost(t, res.stdout)
+	if post.RenderedHtml != "<h1>Hi</h1>\n" {
+		t.Fatalf("unexpected rendered HTML %q", post.RenderedHtml)
+	}
+
+	// update keeps the format unless it is given.
+	res = runClient(t, addr, "", "posts", "update", post.PostId, "-title", "x", "-o", "table", "-columns", "title,format")
+	if !strings.Contains(res.stdout, "x      markdown") {
+		t.Fatalf("expected the format to be kept, got %s%s", res.stdout, res.stderr)
+	}
+
+	res = runClient(t, addr, "", "posts", "create", "-content-format", "rst")
+	if res.code != exitUsage || !strings.Contains(res.stderr, `invalid -content-format "rst"`) {
+		t.Fatalf("expected a usage error, got exit %d: %s", res.code, res.stderr)
+	}
+}
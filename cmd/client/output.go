@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/yaml.v3"
+
+	"grpc-blog/proto/blogpb"
+)
+
+// Output formats.
+const (
+	outputJSON     = "json"
+	outputYAML     = "yaml"
+	outputTable    = "table"
+	outputMarkdown = "markdown"
+)
+
+// columns renders each table column of a post.
+var columns = map[string]func(*blogpb.BlogPost) string{
+	"id":      func(p *blogpb.BlogPost) string { return p.PostId },
+	"title":   func(p *blogpb.BlogPost) string { return p.Title },
+	"author":  func(p *blogpb.BlogPost) string { return p.Author },
+	"date":    func(p *blogpb.BlogPost) string { return formatDate(p) },
+	"tags":    func(p *blogpb.BlogPost) string { return strings.Join(p.Tags, ",") },
+	"slug":    func(p *blogpb.BlogPost) string { return p.Slug },
+	"format":  func(p *blogpb.BlogPost) string { return formatName(p.ContentFormat) },
+	"content": func(p *blogpb.BlogPost) string { return summary(p.Content, 40) },
+}
+
+const defaultColumns = "id,title,author,date,tags"
+
+// printer renders posts in the format chosen by the output flags.
+type printer struct {
+	w       io.Writer
+	format  string
+	columns string
+	quiet   bool
+
+	// recent, if set, records the IDs of printed posts.
+	recent *recentIDs
+}
+
+func (c *cli) bindPrinter(fs *flag.FlagSet, format string) *printer {
+	p := &printer{w: c.stdout, recent: c.recent}
+	fs.StringVar(&p.format, "output", format, "output format: json, yaml, table or markdown")
+	fs.StringVar(&p.format, "o", format, "shorthand for -output")
+	fs.StringVar(&p.columns, "columns", defaultColumns, "table columns: id, title, author, date, tags, slug, format, content")
+	fs.BoolVar(&p.quiet, "quiet", false, "print only post IDs")
+	fs.BoolVar(&p.quiet, "q", false, "shorthand for -quiet")
+	return p
+}
+
+// validate reports unknown formats and columns before any RPC is made.
+func (p *printer) validate() error {
+	switch p.format {
+	case outputJSON, outputYAML, outputTable, outputMarkdown:
+	default:
+		return usageErrorf("unknown output format %q: want json, yaml, table or markdown", p.format)
+	}
+
+	for _, col := range strings.Split(p.columns, ",") {
+		if _, ok := columns[strings.TrimSpace(col)]; !ok {
+			names := slices.Sorted(maps.Keys(columns))
+			return usageErrorf("unknown column %q: want %s or %s", col, strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+		}
+	}
+	return nil
+}
+
+// print writes posts. A single post prints as an object in JSON and YAML;
+// list prints an array even when it holds one post.
+func (p *printer) print(posts []*blogpb.BlogPost, list bool) error {
+	for _, post := range posts {
+		p.recent.add(post.PostId)
+	}
+
+	if p.quiet {
+		for _, post := range posts {
+			if _, err := fmt.Fprintln(p.w, post.PostId); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch p.format {
+	case outputYAML:
+		return p.yaml(posts, list)
+	case outputTable:
+		return p.table(posts)
+	case outputMarkdown:
+		return p.markdown(posts)
+	default:
+		return p.json(posts, list)
+	}
+}
+
+// json uses the proto3 JSON mapping, as the gateway does.
+func (p *printer) json(posts []*blogpb.BlogPost, list bool) error {
+	raw := make([]json.RawMessage, len(posts))
+	for i, post := range posts {
+		b, err := protojson.Marshal(post)
+		if err != nil {
+			return err
+		}
+		raw[i] = b
+	}
+
+	var v any = raw
+	if !list && len(raw) == 1 {
+		v = raw[0]
+	}
+
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(out))
+	return err
+}
+
+// postView is the YAML form of a post.
+type postView struct {
+	PostID          string   `yaml:"post_id"`
+	Title           string   `yaml:"title"`
+	Author          string   `yaml:"author"`
+	PublicationDate string   `yaml:"publication_date,omitempty"`
+	Tags            []string `yaml:"tags,omitempty"`
+	Slug            string   `yaml:"slug,omitempty"`
+	ContentFormat   string   `yaml:"content_format"`
+	Content         string   `yaml:"content"`
+}
+
+func (p *printer) yaml(posts []*blogpb.BlogPost, list bool) error {
+	views := make([]postView, len(posts))
+	for i, post := range posts {
+		views[i] = postView{
+			PostID:          post.PostId,
+			Title:           post.Title,
+			Author:          post.Author,
+			PublicationDate: formatDate(post),
+			Tags:            post.Tags,
+			Slug:            post.Slug,
+			ContentFormat:   formatName(post.ContentFormat),
+			Content:         post.Content,
+		}
+	}
+
+	var v any = views
+	if !list && len(views) == 1 {
+		v = views[0]
+	}
+
+	enc := yaml.NewEncoder(p.w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func (p *printer) table(posts []*blogpb.BlogPost) error {
+	var cols []string
+	for _, col := range strings.Split(p.columns, ",") {
+		cols = append(cols, strings.TrimSpace(col))
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(cols, "\t")))
+	for _, post := range posts {
+		cells := make([]string, len(cols))
+		for i, col := range cols {
+			cells[i] = columns[col](post)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// markdown renders each post as a document: title heading, a byline with
+// the date and tags, then the content. Posts are separated by rules.
+func (p *printer) markdown(posts []*blogpb.BlogPost) error {
+	var b strings.Builder
+	for i, post := range posts {
+		if i > 0 {
+			b.WriteString("\n---\n\n")
+		}
+
+		fmt.Fprintf(&b, "# %s\n\n", post.Title)
+
+		var meta []string
+		if post.Author != "" {
+			meta = append(meta, "by "+post.Author)
+		}
+		if date := formatDate(post); date != "" {
+			meta = append(meta, date)
+		}
+		if len(post.Tags) > 0 {
+			meta = append(meta, "tags: "+strings.Join(post.Tags, ", "))
+		}
+		meta = append(meta, "id: `"+post.PostId+"`")
+		fmt.Fprintf(&b, "_%s_\n", strings.Join(meta, " · "))
+
+		if content := strings.TrimSpace(post.Content); content != "" {
+			fmt.Fprintf(&b, "\n%s\n", content)
+		}
+	}
+
+	_, err := io.WriteString(p.w, b.String())
+	return err
+}
+
+func formatDate(post *blogpb.BlogPost) string {
+	if post.PublicationDate == nil {
+		return ""
+	}
+	return post.PublicationDate.AsTime().UTC().Format(time.RFC3339)
+}
+
+// summary returns the first line of s, cut to n runes.
+func summary(s string, n int) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+
+	"grpc-blog/proto/blogpb"
+)
+
+func testPosts() []*blogpb.BlogPost {
+	return []*blogpb.BlogPost{
+		{
+			PostId:          "p1",
+			Title:           "First",
+			Author:          "ann",
+			Content:         "line one\nline two",
+			Tags:            []string{"go", "grpc"},
+			PublicationDate: timestamppb.New(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)),
+		},
+		{PostId: "p2", Title: "Second", Author: "bob"},
+	}
+}
+
+func render(t *testing.T, p *printer, posts []*blogpb.BlogPost, list bool) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	p.w = &buf
+	if err := p.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if err := p.print(posts, list); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	return buf.String()
+}
+
+func TestTableColumns(t *testing.T) {
+	out := render(t, &printer{format: outputTable, columns: "id, date,tags"}, testPosts(), true)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and two rows, got %q", out)
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "ID DATE TAGS" {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "p1 2024-05-01T09:00:00Z go,grpc" {
+		t.Fatalf("unexpected row %q", lines[1])
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	out := render(t, &printer{format: outputTable, columns: defaultColumns, quiet: true}, testPosts(), true)
+	if out != "p1\np2\n" {
+		t.Fatalf("expected only IDs, got %q", out)
+	}
+}
+
+func TestYAML(t *testing.T) {
+	out := render(t, &printer{format: outputYAML, columns: defaultColumns}, testPosts(), true)
+
+	var views []postView
+	if err := yaml.Unmarshal([]byte(out), &views); err != nil {
+		t.Fatalf("output is not YAML: %v\n%s", err, out)
+	}
+	if len(views) != 2 || views[0].PublicationDate != "2024-05-01T09:00:00Z" || views[0].Content != "line one\nline two" {
+		t.Fatalf("unexpected posts %+v", views)
+	}
+
+	// A single post is a mapping, not a one-item list.
+	single := testPosts()[:1]
+	out = render(t, &printer{format: outputYAML, columns: defaultColumns}, single, false)
+	if !strings.HasPrefix(out, "post_id: p1\n") {
+		t.Fatalf("expected a mapping, got %q", out)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	out := render(t, &printer{format: outputMarkdown, columns: defaultColumns}, testPosts(), true)
+
+	for _, want := range []string{
+		"# First\n",
+		"_by ann · 2024-05-01T09:00:00Z · tags: go, grpc · id: `p1`_\n",
+		"\nline one\nline two\n",
+		"\n---\n\n# Second\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in\n%s", want, out)
+		}
+	}
+}
+
+func TestInvalidOutputFlags(t *testing.T) {
+	addr := startServer(t)
+
+	for _, args := range [][]string{
+		{"posts", "list", "-o", "xml"},
+		{"posts", "list", "-o", "table", "-columns", "id,likes"},
+	} {
+		res := runClient(t, addr, "", args...)
+		if res.code != exitUsage || !strings.Contains(res.stderr, "unknown") {
+			t.Fatalf("%v: expected a usage error, got exit %d: %s", args, res.code, res.stderr)
+		}
+	}
+
+	res := runClient(t, addr, "", "posts", "list", "-o", "table", "-columns", "likes")
+	if want := "want author, content, date, format, id, slug, tags or title"; !strings.Contains(res.stderr, want) {
+		t.Fatalf("expected the error to list every column, got %s", res.stderr)
+	}
+}
+
+func TestListQuietEndToEnd(t *testing.T) {
+	addr := startServer(t)
+
+	res := runClient(t, addr, "", "posts", "create", "-title", "x", "-q")
+	id := strings.TrimSpace(res.stdout)
+	if res.code != exitOK || id == "" || strings.ContainsAny(id, "{\n") {
+		t.Fatalf("create -q: exit %d: %q %s", res.code, res.stdout, res.stderr)
+	}
+
+	res = runClient(t, addr, "", "posts", "list", "--output=table", "--quiet")
+	if res.code != exitOK || strings.TrimSpace(res.stdout) != id {
+		t.Fatalf("list -quiet: exit %d: %q %s", res.code, res.stdout, res.stderr)
+	}
+}
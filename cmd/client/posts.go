@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"grpc-blog/proto/blogpb"
+)
+
+// postsCommands are the posts subcommands, also accepted bare in the shell.
+var postsCommands = []string{"create", "get", "list", "update", "delete"}
+
+const postsUsage = `usage:
+  client posts create [field flags]     create a post
+  client posts get <id>                 print a post
+  client posts list                     print every post
+  client posts update <id> [field flags]
+                                        change the given fields of a post
+  client posts delete <id>              delete a post
+
+Field flags are read on top of -file, so flags win over the file.`
+
+// posts implements the "posts" subcommand over blogclient.
+func (c *cli) posts(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageErrorf(postsUsage)
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "create":
+		return c.createPost(ctx, args)
+	case "get":
+		return c.getPost(ctx, args)
+	case "list":
+		return c.listPosts(ctx, args)
+	case "update":
+		return c.updatePost(ctx, args)
+	case "delete":
+		return c.deletePost(ctx, args)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprintln(c.stderr, postsUsage)
+		return flag.ErrHelp
+	default:
+		return usageErrorf("unknown posts command %q\n\n%s", command, postsUsage)
+	}
+}
+
+func (c *cli) createPost(ctx context.Context, args []string) error {
+	fs := c.flagSet("posts create", "[field flags]")
+	out := c.bindPrinter(fs, outputJSON)
+	in := bindPostInput(fs, true)
+	if err := c.parse(fs, args, 0, out); err != nil {
+		return err
+	}
+
+	post, set, err := in.read(fs, c.stdin)
+	if err != nil {
+		return err
+	}
+	if !set["publicationDate"] {
+		post.PublicationDate = timestamppb.Now()
+	}
+
+	created, err := c.blog.CreatePost(ctx, post)
+	if err != nil {
+		return err
+	}
+	return out.print([]*blogpb.BlogPost{created}, false)
+}
+
+func (c *cli) getPost(ctx context.Context, args []string) error {
+	fs := c.flagSet("posts get", "<id>")
+	out := c.bindPrinter(fs, outputJSON)
+	if err := c.parse(fs, args, 1, out); err != nil {
+		return err
+	}
+
+	post, err := c.blog.GetPost(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return out.print([]*blogpb.BlogPost{post}, false)
+}
+
+// listPosts follows every page before printing, so tables line up.
+func (c *cli) listPosts(ctx context.Context, args []string) error {
+	fs := c.flagSet("posts list", "")
+	out := c.bindPrinter(fs, outputJSON)
+	if err := c.parse(fs, args, 0, out); err != nil {
+		return err
+	}
+
+	var posts []*blogpb.BlogPost
+	for post, err := range c.blog.ListPosts(ctx) {
+		if err != nil {
+			return err
+		}
+		posts = append(posts, post)
+	}
+	return out.print(posts, true)
+}
+
+// updatePost sends only the fields given, which the server changes in one
+// step.
+func (c *cli) updatePost(ctx context.Context, args []string) error {
+	fs := c.flagSet("posts update", "<id> [field flags]")
+	out := c.bindPrinter(fs, outputJSON)
+	in := bindPostInput(fs, false)
+	if err := c.parse(fs, args, 1, out); err != nil {
+		return err
+	}
+
+	post, set, err := in.read(fs, c.stdin)
+	if err != nil {
+		return err
+	}
+	if len(set) == 0 {
+		return usageErrorf("posts update: no fields given")
+	}
+
+	post.PostId = fs.Arg(0)
+	desc := post.ProtoReflect().Descriptor().Fields()
+	var fields []string
+	for key := range set {
+		fields = append(fields, string(desc.ByJSONName(key).Name()))
+	}
+	slices.Sort(fields)
+
+	updated, err := c.blog.UpdatePostFields(ctx, post, fields...)
+	if err != nil {
+		return err
+	}
+	return out.print([]*blogpb.BlogPost{updated}, false)
+}
+
+func (c *cli) deletePost(ctx context.Context, args []string) error {
+	fs := c.flagSet("posts delete", "<id>")
+	quiet := fs.Bool("quiet", false, "print only the post ID")
+	fs.BoolVar(quiet, "q", false, "shorthand for -quiet")
+	if err := c.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	if err := c.blog.DeletePost(ctx, fs.Arg(0)); err != nil {
+		return err
+	}
+
+	c.recent.remove(fs.Arg(0))
+	if *quiet {
+		fmt.Fprintln(c.stdout, fs.Arg(0))
+	} else {
+		fmt.Fprintf(c.stdout, "deleted %s\n", fs.Arg(0))
+	}
+	return nil
+}
+
+// flagSet returns a FlagSet for a posts subcommand whose errors and help
+// go to stderr.
+func (c *cli) flagSet(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "usage: client %s %s\n\nflags:\n", name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args into fs and checks that exactly nargs positional
+// arguments remain and that the output flags, if any, are valid. Flags may
+// follow the positional arguments.
+func (c *cli) parse(fs *flag.FlagSet, args []string, nargs int, out ...*printer) error {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return err
+			}
+			// The FlagSet already printed the error and usage.
+			return &usageError{msg: ""}
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) != nargs {
+		fs.Usage()
+		return &usageError{msg: ""}
+	}
+	for _, p := range out {
+		if err := p.validate(); err != nil {
+			return err
+		}
+	}
+	// Leave the positional arguments where fs.Arg finds them.
+	return fs.Parse(append([]string{"--"}, positional...))
+}
+
+// postInput collects post fields from -file, -content-file and the field
+// flags.
+type postInput struct {
+	file        string
+	contentFile string
+	title       string
+	content     string
+	author      string
+	tags        string
+	slug        string
+	format      string
+	date        string
+}
+
+// contentFormats maps -content-format names to ContentFormat values.
+var contentFormats = map[string]blogpb.ContentFormat{
+	"plain":    blogpb.ContentFormat_CONTENT_FORMAT_PLAIN,
+	"markdown": blogpb.ContentFormat_CONTENT_FORMAT_MARKDOWN,
+	"html":     blogpb.ContentFormat_CONTENT_FORMAT_HTML,
+}
+
+// formatName returns the -content-format name of f. Unspecified content
+// is plain text.
+func formatName(f blogpb.ContentFormat) string {
+	for name, v := range contentFormats {
+		if v == f {
+			return name
+		}
+	}
+	if f == blogpb.ContentFormat_CONTENT_FORMAT_UNSPECIFIED {
+		return "plain"
+	}
+	return f.String()
+}
+
+// bindPostInput binds the field flags. Only -date defaults, to now, on
+// create.
+func bindPostInput(fs *flag.FlagSet, create bool) *postInput {
+	in := new(postInput)
+	fs.StringVar(&in.file, "file", "", `read fields from a JSON post, "-" for stdin`)
+	fs.StringVar(&in.contentFile, "content-file", "", `read the content from a file, "-" for stdin`)
+	fs.StringVar(&in.title, "title", "", "post title")
+	fs.StringVar(&in.content, "content", "", "post content")
+	fs.StringVar(&in.author, "author", "", "post author")
+	fs.StringVar(&in.tags, "tags", "", "comma-separated tags")
+	fs.StringVar(&in.slug, "slug", "", `unique URL key such as "hello-world"`)
+	fs.StringVar(&in.format, "content-format", "", "content format: plain, markdown or html")
+	dateUsage := "publication date, RFC 3339"
+	if create {
+		dateUsage += " (default now)"
+	}
+	fs.StringVar(&in.date, "date", "", dateUsage)
+	return in
+}
+
+// read returns the post described by the input and the set of fields it
+// gave, keyed by proto JSON name.
+func (in *postInput) read(fs *flag.FlagSet, stdin io.Reader) (*blogpb.BlogPost, map[string]bool, error) {
+	if in.file == "-" && in.contentFile == "-" {
+		return nil, nil, usageErrorf("-file and -content-file cannot both read stdin")
+	}
+
+	post := new(blogpb.BlogPost)
+	set := make(map[string]bool)
+
+	if in.file != "" {
+		data, err := readInput(in.file, stdin)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := unmarshalPost(data, post, set); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", in.file, err)
+		}
+	}
+
+	if in.contentFile != "" {
+		data, err := readInput(in.contentFile, stdin)
+		if err != nil {
+			return nil, nil, err
+		}
+		post.Content = string(data)
+		set["content"] = true
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "title":
+			post.Title = in.title
+			set["title"] = true
+		case "content":
+			post.Content = in.content
+			set["content"] = true
+		case "author":
+			post.Author = in.author
+			set["author"] = true
+		case "tags":
+			post.Tags = splitTags(in.tags)
+			set["tags"] = true
+		case "slug":
+			post.Slug = in.slug
+			set["slug"] = true
+		case "content-format":
+			f, ok := contentFormats[in.format]
+			if !ok {
+				err = usageErrorf("invalid -content-format %q: want plain, markdown or html", in.format)
+				return
+			}
+			post.ContentFormat = f
+			set["contentFormat"] = true
+		case "date":
+			t, perr := time.Parse(time.RFC3339, in.date)
+			if perr != nil {
+				err = usageErrorf("invalid -date %q: want RFC 3339, e.g. 2024-05-01T09:00:00Z", in.date)
+				return
+			}
+			post.PublicationDate = timestamppb.New(t)
+			set["publicationDate"] = true
+		}
+	})
+	return post, set, err
+}
+
+// unmarshalPost decodes a JSON post into post and records its fields in
+// set. Unknown fields are ignored, as are postId and renderedHtml in an
+// exported post.
+func unmarshalPost(data []byte, post *blogpb.BlogPost, set map[string]bool) error {
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, post); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	desc := post.ProtoReflect().Descriptor().Fields()
+	for key := range fields {
+		// protojson accepts both the JSON and the proto field name.
+		if fd := desc.ByJSONName(key); fd != nil {
+			set[fd.JSONName()] = true
+		} else if fd := desc.ByName(protoreflect.Name(key)); fd != nil {
+			set[fd.JSONName()] = true
+		}
+	}
+	delete(set, "postId")
+	delete(set, "renderedHtml")
+	return nil
+}
+
+func readInput(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
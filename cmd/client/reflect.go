@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"grpc-blog/internal/dynamic"
+)
+
+const reflectUsage = `usage:
+  client reflect list                     list services
+  client reflect list <service>           list methods of a service
+  client reflect call <service/method> [json|-]
+                                          invoke a method; "-" reads JSON from stdin`
+
+// reflect implements the "reflect" subcommand, which discovers and calls
+// RPCs through server reflection instead of generated stubs.
+func (c *cli) reflect(ctx context.Context, args []string) error {
+	client := dynamic.NewClient(c.conn)
+
+	if len(args) == 0 {
+		return usageErrorf(reflectUsage)
+	}
+
+	switch args[0] {
+	case "list":
+		if len(args) == 1 {
+			services, err := client.ListServices(ctx)
+			if err != nil {
+				return err
+			}
+			for _, name := range services {
+				fmt.Fprintln(c.stdout, name)
+			}
+			return nil
+		}
+
+		svc, err := client.DescribeService(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		methods := svc.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			m := methods.Get(i)
+			fmt.Fprintf(c.stdout, "%s(%s) returns (%s)\n", m.Name(), m.Input().FullName(), m.Output().FullName())
+		}
+		return nil
+
+	case "call":
+		if len(args) < 2 {
+			return usageErrorf(reflectUsage)
+		}
+
+		var input []byte
+		if len(args) > 2 {
+			input = []byte(args[2])
+			if args[2] == "-" {
+				var err error
+				if input, err = io.ReadAll(c.stdin); err != nil {
+					return err
+				}
+			}
+		}
+
+		return client.Invoke(ctx, args[1], input, func(out []byte) error {
+			_, err := fmt.Fprintln(c.stdout, string(out))
+			return err
+		})
+
+	default:
+		return usageErrorf(reflectUsage)
+	}
+}
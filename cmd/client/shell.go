@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/peterh/liner"
+)
+
+const shellHelp = `commands:
+  posts create|get|list|update|delete ...
+                  as on the command line; "posts" may be left out
+  reflect list|call ...
+  backup create|list|restore ...
+                  as on the command line
+  edit <id>       edit the content of a post in $EDITOR and save it
+  help            show this help
+  exit            leave the shell (or Ctrl-D)
+
+Tab completes commands and the IDs of posts seen in this session.`
+
+// shellCommands are completed as the first word of a line.
+var shellCommands = append([]string{"posts", "reflect", "backup", "edit", "help", "exit"}, postsCommands...)
+
+// maxRecentIDs bounds the post IDs offered for completion.
+const maxRecentIDs = 50
+
+// shell implements the "shell" subcommand: a prompt that runs commands over
+// the one connection until exit or end of input. Errors are reported and the
+// prompt carries on.
+func (c *cli) shell(ctx context.Context, args []string) error {
+	fs := c.flagSet("shell", "")
+	history := fs.String("history", defaultHistory(), `history file, "" to keep none`)
+	if err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	c.recent = new(recentIDs)
+	in := c.lineReader(*history)
+	defer in.Close()
+
+	for {
+		line, err := in.Prompt("blog> ")
+		if errors.Is(err, liner.ErrPromptAborted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		words, err := splitWords(line)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "error: %v\n", err)
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		in.AppendHistory(line)
+
+		switch words[0] {
+		case "exit", "quit":
+			return nil
+		case "help":
+			fmt.Fprintln(c.stdout, shellHelp)
+		case "edit":
+			c.exit(c.edit(ctx, words[1:]))
+		case "posts", "reflect", "backup":
+			c.exit(c.dispatch(ctx, words))
+		default:
+			if slices.Contains(postsCommands, words[0]) {
+				c.exit(c.dispatch(ctx, append([]string{"posts"}, words...)))
+			} else {
+				fmt.Fprintf(c.stderr, "unknown command %q; try help\n", words[0])
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// edit opens the content of a post in the user's editor and saves it, and
+// only it, if it changed. Only the RPCs are under the timeout, not the
+// editing.
+func (c *cli) edit(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return usageErrorf("usage: edit <id>")
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, c.timeout)
+	post, err := c.blog.GetPost(rctx, args[0])
+	cancel()
+	if err != nil {
+		return err
+	}
+	c.recent.add(post.PostId)
+
+	content, err := editText(ctx, post.Content)
+	if err != nil {
+		return err
+	}
+	if content == post.Content {
+		fmt.Fprintln(c.stdout, "no changes")
+		return nil
+	}
+
+	uctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	post.Content = content
+	if _, err := c.blog.UpdatePostFields(uctx, post, "content"); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stdout, "updated %s\n", post.PostId)
+	return nil
+}
+
+// editText runs $VISUAL, $EDITOR or vi on a temporary file holding text and
+// returns what the file holds when the editor exits.
+func editText(ctx context.Context, text string) (string, error) {
+	f, err := os.CreateTemp("", "blog-post-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(text)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// A blank variable, such as VISUAL=" ", counts as unset.
+	editor := []string{"vi"}
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(key)); len(fields) > 0 {
+			editor = fields
+			break
+		}
+	}
+	cmd := exec.CommandContext(ctx, editor[0], append(editor[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s: %w", editor[0], err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	return string(data), err
+}
+
+// lineReader reads shell input.
+type lineReader interface {
+	Prompt(prompt string) (string, error)
+	AppendHistory(line string)
+	Close() error
+}
+
+// lineReader returns a line editor with history and completion when stdin
+// is a terminal, and a plain reader for piped input, which prints no
+// prompts.
+func (c *cli) lineReader(history string) lineReader {
+	if f, ok := c.stdin.(*os.File); !ok || f != os.Stdin || !isTerminal(f) {
+		return &scanReader{sc: bufio.NewScanner(c.stdin)}
+	}
+
+	state := liner.NewLiner()
+	state.SetCtrlCAborts(true)
+	state.SetTabCompletionStyle(liner.TabPrints)
+	state.SetWordCompleter(c.complete)
+
+	if f, err := os.Open(history); err == nil {
+		state.ReadHistory(f)
+		f.Close()
+	}
+	return &termReader{State: state, history: history}
+}
+
+// termReader saves history when closed.
+type termReader struct {
+	*liner.State
+	history string
+}
+
+func (r *termReader) Close() error {
+	if r.history != "" {
+		if f, err := os.Create(r.history); err == nil {
+			r.WriteHistory(f)
+			f.Close()
+		}
+	}
+	return r.State.Close()
+}
+
+type scanReader struct{ sc *bufio.Scanner }
+
+func (r *scanReader) Prompt(string) (string, error) {
+	if !r.sc.Scan() {
+		if err := r.sc.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.sc.Text(), nil
+}
+
+func (r *scanReader) AppendHistory(string) {}
+
+func (r *scanReader) Close() error { return nil }
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func defaultHistory() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".blog_client_history")
+}
+
+// complete completes the word before the cursor: a command, a posts or
+// reflect subcommand, or a recently seen post ID.
+func (c *cli) complete(line string, pos int) (head string, completions []string, tail string) {
+	head, tail = line[:pos], line[pos:]
+	start := strings.LastIndexAny(head, " \t") + 1
+	head, word := head[:start], head[start:]
+
+	for _, candidate := range c.candidates(strings.Fields(head)) {
+		if strings.HasPrefix(candidate, word) {
+			completions = append(completions, candidate+" ")
+		}
+	}
+	return head, completions, tail
+}
+
+// candidates returns the completions for the word after words.
+func (c *cli) candidates(words []string) []string {
+	if len(words) == 0 {
+		return shellCommands
+	}
+
+	switch words[0] {
+	case "posts":
+		if len(words) == 1 {
+			return postsCommands
+		}
+		words = words[1:]
+	case "reflect":
+		if len(words) == 1 {
+			return []string{"list", "call"}
+		}
+		return nil
+	case "backup":
+		if len(words) == 1 {
+			return []string{"create", "list", "restore"}
+		}
+		return nil
+	}
+
+	switch words[0] {
+	case "get", "update", "delete", "edit":
+		// Only the ID is positional; flags may follow it.
+		if len(words) == 1 {
+			return c.recent.list()
+		}
+	}
+	return nil
+}
+
+// recentIDs is the set of post IDs seen in the shell, most recent first.
+// Its methods do nothing on a nil receiver, outside the shell.
+type recentIDs struct{ ids []string }
+
+func (r *recentIDs) add(id string) {
+	if r == nil || id == "" {
+		return
+	}
+	r.remove(id)
+	r.ids = append([]string{id}, r.ids...)
+	if len(r.ids) > maxRecentIDs {
+		r.ids = r.ids[:maxRecentIDs]
+	}
+}
+
+func (r *recentIDs) remove(id string) {
+	if r == nil {
+		return
+	}
+	r.ids = slices.DeleteFunc(r.ids, func(s string) bool { return s == id })
+}
+
+func (r *recentIDs) list() []string {
+	if r == nil {
+		return nil
+	}
+	return slices.Clone(r.ids)
+}
+
+// splitWords splits a shell line into words. Single and double quotes
+// group words and a backslash escapes the next character, outside single
+// quotes.
+func splitWords(line string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestShellScript(t *testing.T) {
+	addr := startServer(t)
+
+	script := strings.Join([]string{
+		`create -title "Hello world" -tags a,b -q`,
+		`posts list -o table -columns title,tags`,
+		`bogus`,
+		`get missing`,
+		`list -o yaml`,
+		`exit`,
+		`list`, // never runs
+	}, "\n")
+
+	res := runClient(t, addr, script, "shell", "-history", "")
+	if res.code != exitOK {
+		t.Fatalf("shell: exit %d: %s", res.code, res.stderr)
+	}
+
+	if !strings.Contains(res.stdout, "Hello world  a,b") {
+		t.Errorf("expected the table row, got\n%s", res.stdout)
+	}
+	if strings.Count(res.stdout, "title: Hello world") != 1 {
+		t.Errorf("expected one YAML listing before exit, got\n%s", res.stdout)
+	}
+	for _, want := range []string{`unknown command "bogus"`, "error: post not found"} {
+		if !strings.Contains(res.stderr, want) {
+			t.Errorf("expected %q in stderr, got\n%s", want, res.stderr)
+		}
+	}
+}
+
+func TestShellEdit(t *testing.T) {
+	addr := startServer(t)
+
+	res := runClient(t, addr, "", "posts", "create", "-title", "Draft", "-content", "old", "-tags", "go", "-date", "2024-05-01T09:00:00Z", "-q")
+	id := strings.TrimSpace(res.stdout)
+	if res.code != exitOK {
+		t.Fatalf("create: exit %d: %s", res.code, res.stderr)
+	}
+
+	editor := filepath.Join(t.TempDir(), "editor")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\nprintf 'new content' > \"$1\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+
+	res = runClient(t, addr, "edit "+id+"\nget "+id+" -o yaml\n", "shell", "-history", "")
+	if res.code != exitOK || !strings.Contains(res.stdout, "updated "+id) {
+		t.Fatalf("edit: exit %d: %s%s", res.code, res.stdout, res.stderr)
+	}
+	if !strings.Contains(res.stdout, "content: new content") || !strings.Contains(res.stdout, "title: Draft") ||
+		!strings.Contains(res.stdout, "2024-05-01T09:00:00Z") {
+		t.Fatalf("expected new content with other fields kept, got\n%s", res.stdout)
+	}
+}
+
+func TestEditTextBlankEditor(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "vi"), []byte("#!/bin/sh\nprintf 'from vi' > \"$1\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("VISUAL", " ")
+	t.Setenv("EDITOR", "")
+
+	got, err := editText(context.Background(), "old")
+	if err != nil || got != "from vi" {
+		t.Fatalf("expected a blank $VISUAL to fall back to vi, got %q (%v)", got, err)
+	}
+}
+
+func TestEditTextBlankVisualUsesEditor(t *testing.T) {
+	editor := filepath.Join(t.TempDir(), "editor")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\nprintf 'from $EDITOR' > \"$1\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", " ")
+	t.Setenv("EDITOR", editor)
+
+	got, err := editText(context.Background(), "old")
+	if err != nil || got != "from $EDITOR" {
+		t.Fatalf("expected a blank $VISUAL to fall back to $EDITOR, got %q (%v)", got, err)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	c := &cli{recent: new(recentIDs)}
+	c.recent.add("abc")
+	c.recent.add("abd")
+	c.recent.add("xyz")
+	c.recent.remove("xyz")
+
+	cases := []struct {
+		line string
+		head string
+		want []string
+	}{
+		{"ed", "", []string{"edit "}},
+		{"posts up", "posts ", []string{"update "}},
+		{"get ab", "get ", []string{"abd ", "abc "}},
+		{"posts delete a", "posts delete ", []string{"abd ", "abc "}},
+		{"get abc -o ", "get abc -o ", nil},
+		{"reflect l", "reflect ", []string{"list "}},
+	}
+
+	for _, tc := range cases {
+		head, got, tail := c.complete(tc.line, len(tc.line))
+		if head != tc.head || tail != "" || !slices.Equal(got, tc.want) {
+			t.Errorf("complete(%q) = %q, %q, %q; want %q, %q", tc.line, head, got, tail, tc.head, tc.want)
+		}
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	got, err := splitWords(`create -title "a b" -content 'it''s' -author c\ d ""`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"create", "-title", "a b", "-content", "its", "-author", "c d", ""}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if _, err := splitWords(`get "open`); err == nil {
+		t.Fatal("expected an unterminated quote error")
+	}
+}
@@ -2,78 +2,37 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
-	"net"
 	"os"
 	"os/signal"
 	"syscall"
 
-	"grpc-blog/internal/app/blog"
+	"grpc-blog/internal/config"
 	"grpc-blog/internal/container"
-	"grpc-blog/internal/infra/tracing"
-	grpcTransport "grpc-blog/internal/transport/grpc"
-	grpctransport "grpc-blog/internal/transport/grpc"
-	"grpc-blog/proto/blogpb"
-
-	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
-	"go.uber.org/zap"
-	"google.golang.org/grpc"
 )
 
 func main() {
-	// ---- tracing ----
-	shutdownTracer, err := tracing.InitTracer()
-	if err != nil {
-		log.Fatalf("failed to init tracer: %v", err)
-	}
-	defer shutdownTracer(context.Background())
+	os.Exit(run())
+}
 
-	// ---- DI container ----
-	c, err := container.Build()
+// run returns the process exit code, so deferred cleanup always runs
+// before exiting.
+func run() int {
+	// ---- configuration ----
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return container.ExitOK
+	}
 	if err != nil {
-		log.Fatalf("failed to build container: %v", err)
+		log.Printf("invalid configuration: %v", err)
+		return container.ExitConfig
 	}
 
-	// ---- start server via DI ----
-	err = c.Invoke(func(
-		logger *zap.Logger,
-		service *blog.Service,
-	) {
-		lis, err := net.Listen("tcp", ":50051")
-		if err != nil {
-			logger.Fatal("failed to listen", zap.Error(err))
-		}
-
-		grpcServer := grpc.NewServer(
-			grpc.ChainUnaryInterceptor(
-				grpctransport.UnaryLoggingInterceptor(logger),
-			),
-			grpc.StatsHandler(otelgrpc.NewServerHandler()),
-		)
-
-		blogpb.RegisterBlogServiceServer(
-			grpcServer,
-			grpcTransport.NewBlogGRPCServer(service),
-		)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-		logger.Info("gRPC server started", zap.String("addr", ":50051"))
-
-		// ---- graceful shutdown ----
-		go func() {
-			if err := grpcServer.Serve(lis); err != nil {
-				logger.Fatal("grpc server crashed", zap.Error(err))
-			}
-		}()
-
-		stop := make(chan os.Signal, 1)
-		signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
-		<-stop
-
-		logger.Info("shutting down gRPC server")
-		grpcServer.GracefulStop()
-	})
-
-	if err != nil {
-		log.Fatalf("failed to invoke container: %v", err)
-	}
+	// ---- wire and run via DI ----
+	return container.Run(ctx, container.ServerModules(cfg)...)
 }
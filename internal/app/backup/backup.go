@@ -0,0 +1,358 @@
+// Package backup snapshots the blog store to files and restores it from
+// them while the server keeps running.
+//
+// A backup file is a JSON header line followed by the posts as
+// length-delimited protobuf messages, ordered by post ID:
+//
+//	{"format":"grpc-blog-backup","version":1,"created_at":"…","posts":2,"sha256":"…"}
+//	<varint length><BlogPost><varint length><BlogPost>
+//
+// The checksum covers everything after the header line. Readers accept
+// every format version up to FormatVersion and refuse newer ones, so a
+// backup taken by a newer server is never half understood.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"grpc-blog/internal/app/blog"
+	"grpc-blog/internal/infra/logging"
+	"grpc-blog/proto/blogpb"
+)
+
+// FormatVersion is the version of the file format written by Write.
+const FormatVersion = 1
+
+const (
+	format = "grpc-blog-backup"
+	ext    = ".backup"
+)
+
+// ErrNotFound is returned for a backup name not in the directory.
+var ErrNotFound = errors.New("backup not found")
+
+// ErrCorrupt is returned for a file that is not a backup or whose posts do
+// not match its checksum.
+var ErrCorrupt = errors.New("backup is corrupt")
+
+// ErrIncompatible is returned for a backup in a newer format version.
+var ErrIncompatible = errors.New("backup format version not supported")
+
+// Config locates the backup directory.
+type Config struct {
+	// Dir holds the backup files. Empty disables backups.
+	Dir string `yaml:"dir"`
+}
+
+// Info describes a backup.
+type Info struct {
+	// Name is the file name within the backup directory.
+	Name      string
+	CreatedAt time.Time
+	Posts     int
+	// Size is the file size in bytes.
+	Size int64
+	// SHA256 is the hex checksum of the posts.
+	SHA256  string
+	Version int
+}
+
+// header is the first line of a backup file.
+type header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Posts     int       `json:"posts"`
+	SHA256    string    `json:"sha256"`
+}
+
+func (h header) info(name string, size int64) Info {
+	return Info{Name: name, CreatedAt: h.CreatedAt, Posts: h.Posts, Size: size, SHA256: h.SHA256, Version: h.Version}
+}
+
+// Write encodes posts as a backup taken at created.
+func Write(w io.Writer, posts []*blogpb.BlogPost, created time.Time) (Info, error) {
+	var body bytes.Buffer
+	for _, post := range posts {
+		if _, err := protodelim.MarshalTo(&body, post); err != nil {
+			return Info{}, err
+		}
+	}
+	sum := sha256.Sum256(body.Bytes())
+
+	h := header{
+		Format:    format,
+		Version:   FormatVersion,
+		CreatedAt: created.UTC(),
+		Posts:     len(posts),
+		SHA256:    hex.EncodeToString(sum[:]),
+	}
+	line, err := json.Marshal(h)
+	if err != nil {
+		return Info{}, err
+	}
+	line = append(line, '\n')
+	size := int64(len(line) + body.Len())
+
+	if _, err := w.Write(line); err != nil {
+		return Info{}, err
+	}
+	if _, err := body.WriteTo(w); err != nil {
+		return Info{}, err
+	}
+	return h.info("", size), nil
+}
+
+// Read decodes a backup after checking its version and checksum.
+func Read(r io.Reader) (Info, []*blogpb.BlogPost, error) {
+	br := bufio.NewReader(r)
+	h, n, err := readHeader(br)
+	if err != nil {
+		return Info{}, nil, err
+	}
+
+	body, err := io.ReadAll(br)
+	if err != nil {
+		return Info{}, nil, err
+	}
+	if sum := sha256.Sum256(body); hex.EncodeToString(sum[:]) != h.SHA256 {
+		return Info{}, nil, fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+	}
+
+	var posts []*blogpb.BlogPost
+	opts := protodelim.UnmarshalOptions{MaxSize: -1}
+	for rd := bytes.NewReader(body); rd.Len() > 0; {
+		post := new(blogpb.BlogPost)
+		if err := opts.UnmarshalFrom(rd, post); err != nil {
+			return Info{}, nil, fmt.Errorf("%w: post %d: %v", ErrCorrupt, len(posts)+1, err)
+		}
+		posts = append(posts, post)
+	}
+	if len(posts) != h.Posts {
+		return Info{}, nil, fmt.Errorf("%w: %d posts, header says %d", ErrCorrupt, len(posts), h.Posts)
+	}
+
+	return h.info("", int64(n+len(body))), posts, nil
+}
+
+// readHeader reads and checks the header line, returning its length.
+func readHeader(r *bufio.Reader) (header, int, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, bufio.ErrBufferFull) {
+			err = fmt.Errorf("%w: no header", ErrCorrupt)
+		}
+		return header{}, 0, err
+	}
+
+	var h header
+	if err := json.Unmarshal(line, &h); err != nil || h.Format != format {
+		return header{}, 0, fmt.Errorf("%w: not a backup file", ErrCorrupt)
+	}
+	if h.Version < 1 || h.Version > FormatVersion {
+		return header{}, 0, fmt.Errorf("%w: version %d, this server reads up to %d", ErrIncompatible, h.Version, FormatVersion)
+	}
+	return h, len(line), nil
+}
+
+// Manager keeps backups of a blog.Service in a directory.
+type Manager struct {
+	dir     string
+	service *blog.Service
+	logger  *zap.Logger
+	now     func() time.Time
+
+	// mu serializes Create, so two backups never get the same name.
+	mu sync.Mutex
+}
+
+// NewManager returns a Manager for cfg.Dir. The directory is created with
+// the first backup.
+func NewManager(cfg Config, logger *zap.Logger, service *blog.Service) *Manager {
+	return &Manager{dir: cfg.Dir, service: service, logger: logger, now: time.Now}
+}
+
+// Create writes a snapshot of every post to a new file.
+//
+// The file is written under a temporary name, synced and then renamed, so
+// List and Restore never see a partial backup.
+//
+// Thread-safe.
+func (m *Manager) Create(ctx context.Context) (Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	posts, err := m.service.Snapshot(ctx)
+	if err != nil {
+		return Info{}, err
+	}
+
+	created := m.now().UTC()
+	name := created.Format("20060102T150405.000000000Z") + ext
+	path := filepath.Join(m.dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return Info{}, fmt.Errorf("backup %s already exists", name)
+	}
+
+	if err := os.MkdirAll(m.dir, 0o750); err != nil {
+		return Info{}, err
+	}
+	tmp, err := os.CreateTemp(m.dir, ".tmp-*")
+	if err != nil {
+		return Info{}, err
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly after the rename
+
+	w := bufio.NewWriter(tmp)
+	info, err := Write(w, posts, created)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	syncDir(m.dir)
+
+	info.Name = name
+	logging.FromContext(ctx, m.logger).Info("backup created",
+		zap.String("backup", name),
+		zap.Int("posts", info.Posts),
+		zap.Int64("size_bytes", info.Size),
+	)
+	return info, nil
+}
+
+// List describes the backups in the directory, newest first. Files whose
+// header cannot be read are logged and left out; their posts are only
+// checked by Restore.
+//
+// Thread-safe.
+func (m *Manager) List(ctx context.Context) ([]Info, error) {
+	entries, err := os.ReadDir(m.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []Info
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !validName(e.Name()) {
+			continue
+		}
+		info, err := m.stat(e.Name())
+		if err != nil {
+			logging.FromContext(ctx, m.logger).Warn("unreadable backup",
+				zap.String("backup", e.Name()),
+				zap.Error(err),
+			)
+			continue
+		}
+		infos = append(infos, info)
+	}
+
+	slices.SortFunc(infos, func(a, b Info) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), strings.Compare(b.Name, a.Name))
+	})
+	return infos, nil
+}
+
+// stat reads the header of the named backup.
+func (m *Manager) stat(name string) (Info, error) {
+	f, err := os.Open(filepath.Join(m.dir, name))
+	if err != nil {
+		return Info{}, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return Info{}, err
+	}
+	h, _, err := readHeader(bufio.NewReader(f))
+	if err != nil {
+		return Info{}, err
+	}
+	return h.info(name, fi.Size()), nil
+}
+
+// Restore verifies the named backup and replaces every post with its
+// posts in one step. Nothing changes if verification fails.
+//
+// Output:
+// - ErrNotFound for an unknown name
+// - ErrCorrupt or ErrIncompatible if the file fails verification
+// - blog.ErrInvalidPost, ErrInvalidSlug and the like for refused posts
+//
+// Thread-safe.
+func (m *Manager) Restore(ctx context.Context, name string) (Info, error) {
+	if !validName(name) {
+		return Info{}, ErrNotFound
+	}
+	f, err := os.Open(filepath.Join(m.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return Info{}, ErrNotFound
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	defer f.Close()
+
+	info, posts, err := Read(f)
+	if err != nil {
+		return Info{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if err := m.service.Restore(ctx, posts); err != nil {
+		return Info{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	info.Name = name
+	logging.FromContext(ctx, m.logger).Info("backup restored",
+		zap.String("backup", name),
+		zap.Int("posts", info.Posts),
+	)
+	return info, nil
+}
+
+// validName reports whether name is a backup file name, which also keeps
+// Restore from reading outside the directory.
+func validName(name string) bool {
+	return strings.HasSuffix(name, ext) && !strings.HasPrefix(name, ".") && filepath.Base(name) == name
+}
+
+// syncDir makes a rename in dir durable. Errors are ignored: some
+// platforms cannot sync directories.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
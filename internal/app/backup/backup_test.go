@@ -0,0 +1,173 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/protobuf/proto"
+
+	"grpc-blog/internal/app/blog"
+	"grpc-blog/proto/blogpb"
+)
+
+func newTestManager(t *testing.T) (*Manager, *blog.Service) {
+	t.Helper()
+
+	logger := zaptest.NewLogger(t)
+	service := blog.NewService(logger, blog.NewMemoryStore())
+	m := NewManager(Config{Dir: filepath.Join(t.TempDir(), "backups")}, logger, service)
+
+	// Every backup gets its own second, so names and order are predictable.
+	clock := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	m.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	return m, service
+}
+
+func TestWriteRead(t *testing.T) {
+	posts := []*blogpb.BlogPost{
+		{PostId: "a", Title: "One", Tags: []string{"go"}},
+		{PostId: "b", Title: "Two", Content: strings.Repeat("x", 10000)},
+	}
+	created := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	written, err := Write(&buf, posts, created)
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if written.Size != int64(buf.Len()) || written.Posts != 2 || written.Version != FormatVersion {
+		t.Fatalf("unexpected info %+v for %d bytes", written, buf.Len())
+	}
+
+	read, got, err := Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if read != written || !read.CreatedAt.Equal(created) {
+		t.Fatalf("read %+v, wrote %+v", read, written)
+	}
+	if len(got) != 2 || !proto.Equal(got[0], posts[0]) || !proto.Equal(got[1], posts[1]) {
+		t.Fatalf("unexpected posts %v", got)
+	}
+
+	if _, got, err := Read(strings.NewReader(mustWrite(t, nil))); err != nil || len(got) != 0 {
+		t.Fatalf("expected an empty backup to read back empty, got %v, %v", got, err)
+	}
+}
+
+func mustWrite(t *testing.T, posts []*blogpb.BlogPost) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if _, err := Write(&buf, posts, time.Now()); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return buf.String()
+}
+
+func TestReadRejects(t *testing.T) {
+	valid := mustWrite(t, []*blogpb.BlogPost{{PostId: "a", Title: "One"}})
+	head, body, _ := strings.Cut(valid, "\n")
+
+	cases := map[string]struct {
+		data string
+		want error
+	}{
+		"empty":          {"", ErrCorrupt},
+		"not a backup":   {"{\"format\":\"other\"}\n" + body, ErrCorrupt},
+		"flipped byte":   {head + "\n" + strings.Replace(body, "One", "Onf", 1), ErrCorrupt},
+		"truncated":      {valid[:len(valid)-1], ErrCorrupt},
+		"newer version":  {strings.Replace(valid, `"version":1`, `"version":2`, 1), ErrIncompatible},
+		"zero version":   {strings.Replace(valid, `"version":1`, `"version":0`, 1), ErrIncompatible},
+		"wrong count":    {strings.Replace(valid, `"posts":1`, `"posts":2`, 1), ErrCorrupt},
+		"garbage header": {"\x00\x01\n" + body, ErrCorrupt},
+	}
+	for name, tc := range cases {
+		if _, _, err := Read(strings.NewReader(tc.data)); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestCreateListRestore(t *testing.T) {
+	m, service := newTestManager(t)
+	ctx := context.Background()
+
+	if infos, err := m.List(ctx); err != nil || len(infos) != 0 {
+		t.Fatalf("expected no backups before the directory exists, got %v, %v", infos, err)
+	}
+
+	post, _ := service.CreatePost(ctx, &blogpb.BlogPost{Title: "One", Slug: "one"})
+	first, err := m.Create(ctx)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	service.CreatePost(ctx, &blogpb.BlogPost{Title: "Two"})
+	second, err := m.Create(ctx)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if first.Name != "20240501T090001.000000000Z.backup" || first.Posts != 1 || second.Posts != 2 {
+		t.Fatalf("unexpected backups %+v, %+v", first, second)
+	}
+
+	// Temporary and foreign files are not backups.
+	os.WriteFile(filepath.Join(m.dir, ".tmp-123"), []byte("partial"), 0o600)
+	os.WriteFile(filepath.Join(m.dir, "notes.txt"), []byte("hi"), 0o600)
+	os.WriteFile(filepath.Join(m.dir, "broken.backup"), []byte("junk"), 0o600)
+
+	infos, err := m.List(ctx)
+	if err != nil || len(infos) != 2 || infos[0] != second || infos[1] != first {
+		t.Fatalf("expected both backups newest first, got %+v, %v", infos, err)
+	}
+
+	service.DeletePost(ctx, post.PostId)
+	restored, err := m.Restore(ctx, first.Name)
+	if err != nil || restored != first {
+		t.Fatalf("restore: %+v, %v", restored, err)
+	}
+	posts, _ := service.Snapshot(ctx)
+	if len(posts) != 1 || posts[0].PostId != post.PostId || posts[0].Slug != "one" {
+		t.Fatalf("expected the first backup's post, got %v", posts)
+	}
+}
+
+func TestRestoreRejects(t *testing.T) {
+	m, service := newTestManager(t)
+	ctx := context.Background()
+
+	service.CreatePost(ctx, &blogpb.BlogPost{Title: "One"})
+	info, err := m.Create(ctx)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	service.CreatePost(ctx, &blogpb.BlogPost{Title: "Two"})
+
+	for _, name := range []string{"missing.backup", "../" + info.Name, info.Name[:len(info.Name)-len(ext)], ".tmp-1.backup"} {
+		if _, err := m.Restore(ctx, name); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%q: expected ErrNotFound, got %v", name, err)
+		}
+	}
+
+	path := filepath.Join(m.dir, info.Name)
+	data, _ := os.ReadFile(path)
+	os.WriteFile(path, bytes.Replace(data, []byte("One"), []byte("Onf"), 1), 0o600)
+	if _, err := m.Restore(ctx, info.Name); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected ErrCorrupt, got %v", err)
+	}
+
+	// A rejected backup changes nothing.
+	if posts, _ := service.Snapshot(ctx); len(posts) != 2 {
+		t.Fatalf("expected both posts to remain, got %d", len(posts))
+	}
+}
@@ -0,0 +1,70 @@
+package blog
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+
+	"grpc-blog/proto/blogpb"
+)
+
+// ErrInvalidContentFormat is returned for a ContentFormat value the
+// service does not know.
+var ErrInvalidContentFormat = errors.New("invalid content format")
+
+// Both are safe for concurrent use.
+var (
+	// markdown renders GitHub Flavored Markdown. Raw HTML is passed
+	// through, since htmlPolicy sanitizes the result.
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	// htmlPolicy allows the formatting, links and images of user content
+	// and drops scripts, event handlers, styles and javascript: URLs.
+	htmlPolicy = bluemonday.UGCPolicy()
+)
+
+// render sets post.RenderedHtml from its content. HTML content is itself
+// replaced by its sanitized form, so no stored field carries markup the
+// policy rejects.
+func render(post *blogpb.BlogPost) error {
+	switch post.ContentFormat {
+	case blogpb.ContentFormat_CONTENT_FORMAT_UNSPECIFIED, blogpb.ContentFormat_CONTENT_FORMAT_PLAIN:
+		post.RenderedHtml = renderPlain(post.Content)
+	case blogpb.ContentFormat_CONTENT_FORMAT_MARKDOWN:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(post.Content), &buf); err != nil {
+			return err
+		}
+		post.RenderedHtml = htmlPolicy.Sanitize(buf.String())
+	case blogpb.ContentFormat_CONTENT_FORMAT_HTML:
+		post.Content = htmlPolicy.Sanitize(post.Content)
+		post.RenderedHtml = post.Content
+	default:
+		return ErrInvalidContentFormat
+	}
+	return nil
+}
+
+// renderPlain escapes text and wraps each paragraph, separated by blank
+// lines, in <p>. Single line breaks become <br>.
+func renderPlain(text string) string {
+	var b strings.Builder
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if para = strings.Trim(para, "\n"); strings.TrimSpace(para) == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(para), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
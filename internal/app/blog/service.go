@@ -1,15 +1,54 @@
 package blog
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
-	"sync"
+	"fmt"
+	"strings"
+	"unicode"
 
+	"grpc-blog/internal/infra/logging"
 	"grpc-blog/proto/blogpb"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 )
 
+const tracerName = "grpc-blog/internal/app/blog"
+
+// ErrNotFound is returned when the requested post does not exist.
+var ErrNotFound = errors.New("post not found")
+
+// ErrInvalidPageToken is returned for a page token ReadPage did not issue.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// ErrInvalidSlug is returned for a slug other than lower case letters and
+// digits in groups joined by single dashes.
+var ErrInvalidSlug = errors.New("invalid slug")
+
+// ErrSlugTaken is returned when another post already has the slug.
+var ErrSlugTaken = errors.New("slug already in use")
+
+// ErrInvalidUpdateMask is returned by UpdatePost for a field name that is
+// not a mutable field of a post.
+var ErrInvalidUpdateMask = errors.New("invalid update mask")
+
+// ErrInvalidPost is returned by Restore for posts that cannot be stored
+// together, e.g. two with the same ID.
+var ErrInvalidPost = errors.New("invalid post")
+
+// maxSlugLen bounds the length of a slug in bytes.
+const maxSlugLen = 100
+
+// MaxPageSize caps the posts returned by one ReadPage call.
+const MaxPageSize = 1000
+
 // Service encapsulates all business logic related to blog posts.
 //
 // Responsibilities:
@@ -18,67 +57,105 @@ import (
 // - Remain independent of transport (gRPC / HTTP)
 // - Be safe for concurrent access
 //
-// Persistence is in-memory by design and can be replaced with a database
-// without affecting callers.
+// Persistence is delegated to a Store, in memory by default, so it can be
+// replaced with a database without affecting callers.
 type Service struct {
-	mu     sync.RWMutex
-	posts  map[string]*blogpb.BlogPost
+	store  Store
 	logger *zap.Logger
+	tracer trace.Tracer
 }
 
 // NewService constructs a new blog Service.
 //
 // Inputs:
-// - logger: structured logger used for domain-level events
+// - logger: fallback logger for domain-level events outside a request
+// - store: persistence for posts, e.g. NewMemoryStore()
 //
 // Output:
-// - Initialized *Service with empty in-memory storage
+// - Initialized *Service backed by store
 //
 // This function performs no I/O and never returns an error.
-func NewService(logger *zap.Logger) *Service {
+func NewService(logger *zap.Logger, store Store) *Service {
 	return &Service{
-		posts:  make(map[string]*blogpb.BlogPost),
+		store:  store,
 		logger: logger,
+		tracer: otel.Tracer(tracerName),
+	}
+}
+
+// clonePost deep-copies post. The service copies posts on the way into and
+// out of the store so callers never share memory with stored posts.
+func clonePost(post *blogpb.BlogPost) *blogpb.BlogPost {
+	return proto.Clone(post).(*blogpb.BlogPost)
+}
+
+// startSpan opens a domain span for the named operation as a child of ctx.
+func (s *Service) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "blog."+name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
+	span.End()
 }
 
 // Create creates a new blog post.
 //
 // Business behavior:
 // - Generates a unique PostID
-// - Stores the post in memory
+// - Rejects an invalid slug or one another post has
+// - Sanitizes HTML content and renders the content to RenderedHtml
+// - Stores a copy of the post; the caller's post is left untouched
 // - Logs the creation event
 //
 // Inputs:
+// - ctx: request context carrying the parent span and request logger
 // - post: BlogPost without PostID
 //
 // Output:
-// - Stored BlogPost with PostID populated
-// - Error only if invariants are violated (currently none)
+// - Copy of the stored BlogPost with PostID and RenderedHtml populated
+// - ErrInvalidSlug or ErrSlugTaken for an unusable slug
+// - ErrInvalidContentFormat for an unknown ContentFormat
+// - Error if the store fails
 //
 // Thread-safe.
-func (s *Service) CreatePost(post *blogpb.BlogPost) (*blogpb.BlogPost, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *Service) CreatePost(ctx context.Context, post *blogpb.BlogPost) (_ *blogpb.BlogPost, err error) {
+	ctx, span := s.startSpan(ctx, "CreatePost", attribute.String("blog.author", post.Author))
+	defer func() { endSpan(span, err) }()
 
-	post.PostId = uuid.New().String()
-	s.posts[post.PostId] = post
+	stored := clonePost(post)
+	stored.PostId = uuid.New().String()
+	if err := render(stored); err != nil {
+		return nil, err
+	}
+	if stored.Slug != "" && !validSlug(stored.Slug) {
+		return nil, ErrInvalidSlug
+	}
+	if err := s.store.Insert(ctx, stored); err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("blog.post_id", stored.PostId))
 
-	s.logger.Info("post created",
-		zap.String("post_id", post.PostId),
-		zap.String("author", post.Author),
+	logging.FromContext(ctx, s.logger).Info("post created",
+		zap.String("post_id", stored.PostId),
+		zap.String("author", stored.Author),
 	)
 
-	return post, nil
+	return clonePost(stored), nil
 }
 
 // Read retrieves a blog post by PostID.
 //
 // Business behavior:
 // - Validates existence
-// - Returns a copy-safe reference
+// - Returns a copy the caller may modify freely
 //
 // Inputs:
+// - ctx: request context carrying the parent span and request logger
 // - id: unique identifier of the blog post
 //
 // Output:
@@ -86,53 +163,104 @@ func (s *Service) CreatePost(post *blogpb.BlogPost) (*blogpb.BlogPost, error) {
 // - Error if post does not exist
 //
 // Thread-safe.
-func (s *Service) ReadPost(id string) (*blogpb.BlogPost, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+func (s *Service) ReadPost(ctx context.Context, id string) (_ *blogpb.BlogPost, err error) {
+	ctx, span := s.startSpan(ctx, "ReadPost", attribute.String("blog.post_id", id))
+	defer func() { endSpan(span, err) }()
 
-	post, ok := s.posts[id]
-	if !ok {
-		return nil, errors.New("post not found")
+	post, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	s.logger.Info("post read",
+	logging.FromContext(ctx, s.logger).Info("post read",
 		zap.String("post_id", post.PostId),
 		zap.String("author", post.Author),
 	)
 
-	return post, nil
+	return clonePost(post), nil
 }
 
-// Read retrieves a blog post by PostID.
+// Page is one page of posts from ReadPage.
+type Page struct {
+	Posts []*blogpb.BlogPost
+	// NextPageToken fetches the next page; empty on the last page.
+	NextPageToken string
+}
+
+// ReadPage retrieves posts ordered by PostID, a page at a time.
 //
 // Business behavior:
-// - Validates existence
-// - Returns a copy-safe reference
+// - Starts after the post the token points at, or at the first post
+// - Returns at most size posts, capped at MaxPageSize; 0 means all the rest
+// - Returns copies the caller may modify freely
 //
 // Inputs:
-// - id: unique identifier of the blog post
+// - ctx: request context carrying the parent span and request logger
+// - size: page size, 0 for no limit
+// - token: NextPageToken of the previous page, empty for the first page
 //
 // Output:
-// - BlogPost if found
-// - Error if post does not exist
+// - Page of posts and the token for the next one
+// - ErrInvalidPageToken if token was not issued by ReadPage
+//
+// Posts created or deleted between calls are seen or skipped according to
+// their PostID; no post is returned twice.
 //
 // Thread-safe.
-func (s *Service) ReadAll() ([]*blogpb.BlogPost, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+func (s *Service) ReadPage(ctx context.Context, size int, token string) (_ Page, err error) {
+	ctx, span := s.startSpan(ctx, "ReadPage", attribute.Int("blog.page_size", size))
+	defer func() { endSpan(span, err) }()
 
-	var result []*blogpb.BlogPost
-	for _, post := range s.posts {
-		result = append(result, post)
-		go func() {
-			s.logger.Info("post read",
-				zap.String("post_id", post.PostId),
-				zap.String("author", post.Author),
-			)
-		}()
+	after, err := decodePageToken(token)
+	if err != nil {
+		return Page{}, err
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
 	}
 
-	return result, nil
+	// One post more than the page tells whether another page follows.
+	limit := 0
+	if size > 0 {
+		limit = size + 1
+	}
+	posts, err := s.store.ListAfter(ctx, after, limit)
+	if err != nil {
+		return Page{}, err
+	}
+
+	var page Page
+	if size > 0 && len(posts) > size {
+		posts = posts[:size]
+		page.NextPageToken = encodePageToken(posts[size-1].PostId)
+	}
+	for _, post := range posts {
+		page.Posts = append(page.Posts, clonePost(post))
+	}
+
+	span.SetAttributes(attribute.Int("blog.post_count", len(page.Posts)))
+	logging.FromContext(ctx, s.logger).Debug("posts paged",
+		zap.Int("count", len(page.Posts)),
+		zap.Bool("more", page.NextPageToken != ""),
+	)
+
+	return page, nil
+}
+
+// Page tokens are opaque to clients: the last PostID of the page, encoded.
+func encodePageToken(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+func decodePageToken(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	id, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(id) == 0 {
+		return "", ErrInvalidPageToken
+	}
+	return string(id), nil
 }
 
 // Update modifies an existing blog post.
@@ -140,60 +268,267 @@ func (s *Service) ReadAll() ([]*blogpb.BlogPost, error) {
 // Business behavior:
 // - Validates that the post exists
 // - Preserves PostID
-// - Overwrites mutable fields
+// - Overwrites the named fields, or every mutable field, with copies from post
+// - Clears a named field that is unset in post
+// - Rejects an invalid slug or one another post has
+// - Sanitizes HTML content and renders the content to RenderedHtml
+// - Reads and writes the post in one store step, so no update is lost
 //
 // Inputs:
+// - ctx: request context carrying the parent span and request logger
 // - id: identifier of the post to update
 // - post: new blog post content
+// - fields: proto names of the fields to change, e.g. "title"; none for all
 //
 // Output:
-// - Updated BlogPost
+// - Copy of the updated BlogPost
+// - ErrInvalidUpdateMask for a field that cannot be updated
+// - ErrInvalidSlug or ErrSlugTaken for an unusable slug
+// - ErrInvalidContentFormat for an unknown ContentFormat
 // - Error if post does not exist
 //
 // Thread-safe.
-func (s *Service) UpdatePost(id string, post *blogpb.BlogPost) (*blogpb.BlogPost, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *Service) UpdatePost(ctx context.Context, id string, post *blogpb.BlogPost, fields ...string) (_ *blogpb.BlogPost, err error) {
+	ctx, span := s.startSpan(ctx, "UpdatePost",
+		attribute.String("blog.post_id", id),
+		attribute.StringSlice("blog.fields", fields),
+	)
+	defer func() { endSpan(span, err) }()
 
-	if _, ok := s.posts[id]; !ok {
-		return nil, errors.New("post not found")
+	if len(fields) == 0 {
+		fields = mutableFields
 	}
-	post.PostId = id
-	s.posts[id] = post
+	update := clonePost(post)
 
-	s.logger.Info("post updated",
-		zap.String("post_id", post.PostId),
-		zap.String("author", post.Author),
+	stored, err := s.store.Update(ctx, id, func(current *blogpb.BlogPost) (*blogpb.BlogPost, error) {
+		next := clonePost(current)
+		for _, field := range fields {
+			if err := setField(next, update, field); err != nil {
+				return nil, err
+			}
+		}
+		if err := render(next); err != nil {
+			return nil, err
+		}
+		if next.Slug != "" && !validSlug(next.Slug) {
+			return nil, ErrInvalidSlug
+		}
+		return next, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx, s.logger).Info("post updated",
+		zap.String("post_id", stored.PostId),
+		zap.String("author", stored.Author),
 	)
 
-	return post, nil
+	return clonePost(stored), nil
+}
+
+// mutableFields are the fields UpdatePost changes when none are named.
+var mutableFields = []string{"title", "content", "author", "publication_date", "tags", "slug", "content_format"}
+
+// setField copies the field named by its proto name from src to dst.
+func setField(dst, src *blogpb.BlogPost, field string) error {
+	switch field {
+	case "title":
+		dst.Title = src.Title
+	case "content":
+		dst.Content = src.Content
+	case "author":
+		dst.Author = src.Author
+	case "publication_date":
+		dst.PublicationDate = src.PublicationDate
+	case "tags":
+		dst.Tags = src.Tags
+	case "slug":
+		dst.Slug = src.Slug
+	case "content_format":
+		dst.ContentFormat = src.ContentFormat
+	default:
+		return fmt.Errorf("%w: unknown field %q", ErrInvalidUpdateMask, field)
+	}
+	return nil
+}
+
+func validSlug(slug string) bool {
+	if len(slug) > maxSlugLen || strings.HasPrefix(slug, "-") || strings.HasSuffix(slug, "-") || strings.Contains(slug, "--") {
+		return false
+	}
+	for _, r := range slug {
+		if r != '-' && !unicode.IsDigit(r) && !(unicode.IsLetter(r) && unicode.ToLower(r) == r) {
+			return false
+		}
+	}
+	return true
 }
 
 // Delete removes a blog post permanently.
 //
 // Business behavior:
 // - Validates existence
-// - Deletes from in-memory store
+// - Deletes from the store
 //
 // Inputs:
+// - ctx: request context carrying the parent span and request logger
 // - id: identifier of the post to delete
 //
 // Output:
 // - Error if post does not exist
 //
 // Thread-safe.
-func (s *Service) DeletePost(id string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *Service) DeletePost(ctx context.Context, id string) (err error) {
+	ctx, span := s.startSpan(ctx, "DeletePost", attribute.String("blog.post_id", id))
+	defer func() { endSpan(span, err) }()
 
-	if _, ok := s.posts[id]; !ok {
-		return errors.New("post not found")
+	if err := s.store.Delete(ctx, id); err != nil {
+		return err
 	}
-	delete(s.posts, id)
 
-	s.logger.Info("post deleted",
+	logging.FromContext(ctx, s.logger).Info("post deleted",
 		zap.String("post_id", id),
 	)
 
 	return nil
 }
+
+// Snapshot returns every post as of one instant, for backups.
+//
+// Business behavior:
+// - Reads the store once, so no write is half applied in the result
+// - Returns copies ordered by PostID
+//
+// Output:
+// - Copies of every post
+// - Error if the store fails
+//
+// Thread-safe.
+func (s *Service) Snapshot(ctx context.Context) (_ []*blogpb.BlogPost, err error) {
+	ctx, span := s.startSpan(ctx, "Snapshot")
+	defer func() { endSpan(span, err) }()
+
+	posts, err := s.store.ListAfter(ctx, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*blogpb.BlogPost, len(posts))
+	for i, post := range posts {
+		result[i] = clonePost(post)
+	}
+
+	span.SetAttributes(attribute.Int("blog.post_count", len(result)))
+	return result, nil
+}
+
+// Restore replaces every post with posts, e.g. from a Snapshot.
+//
+// Business behavior:
+// - Rejects posts without an ID, duplicate IDs and invalid or shared slugs
+// - Renders every post again, so RenderedHtml follows the current policy
+// - Swaps the store contents in one step, so readers never see a mix
+//
+// Inputs:
+// - ctx: request context carrying the parent span and request logger
+// - posts: the complete new set of posts; copies are stored
+//
+// Output:
+// - ErrInvalidPost for a missing or duplicate ID
+// - ErrInvalidSlug, ErrSlugTaken or ErrInvalidContentFormat, with the ID
+// - Error if the store fails
+//
+// A write racing with Restore is applied either before it, and lost, or
+// after it.
+//
+// Thread-safe.
+func (s *Service) Restore(ctx context.Context, posts []*blogpb.BlogPost) (err error) {
+	ctx, span := s.startSpan(ctx, "Restore", attribute.Int("blog.post_count", len(posts)))
+	defer func() { endSpan(span, err) }()
+
+	ids := make(map[string]bool, len(posts))
+	slugs := make(map[string]bool)
+	stored := make([]*blogpb.BlogPost, len(posts))
+	for i, post := range posts {
+		switch {
+		case post.PostId == "":
+			return fmt.Errorf("%w: no post ID", ErrInvalidPost)
+		case ids[post.PostId]:
+			return fmt.Errorf("%w: duplicate post ID %s", ErrInvalidPost, post.PostId)
+		case post.Slug != "" && !validSlug(post.Slug):
+			return fmt.Errorf("post %s: %w", post.PostId, ErrInvalidSlug)
+		case post.Slug != "" && slugs[post.Slug]:
+			return fmt.Errorf("post %s: %w", post.PostId, ErrSlugTaken)
+		}
+		ids[post.PostId] = true
+		if post.Slug != "" {
+			slugs[post.Slug] = true
+		}
+		stored[i] = clonePost(post)
+		if err := render(stored[i]); err != nil {
+			return fmt.Errorf("post %s: %w", post.PostId, err)
+		}
+	}
+
+	if err := s.store.ReplaceAll(ctx, stored); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx, s.logger).Info("posts restored",
+		zap.Int("count", len(stored)),
+	)
+
+	return nil
+}
+
+// HealthCheck reports whether the post store is usable.
+//
+// It delegates to Store.Ping so persistent backends plug into server
+// health reporting.
+//
+// Thread-safe.
+func (s *Service) HealthCheck(ctx context.Context) error {
+	return s.store.Ping(ctx)
+}
+
+// Stats is a point-in-time summary of the post store.
+type Stats struct {
+	// TotalPosts is the number of stored posts.
+	TotalPosts int
+	// PostsPerTag counts posts carrying each tag.
+	PostsPerTag map[string]int
+	// StoreSizeBytes is the encoded protobuf size of all stored posts.
+	StoreSizeBytes int
+}
+
+// Stats computes aggregate statistics over the stored posts.
+//
+// Business behavior:
+// - Counts posts overall and per tag
+// - Approximates store size by the wire size of each post
+//
+// Output:
+// - Stats snapshot of the posts listed by the store
+// - Error if the store fails
+//
+// Thread-safe.
+func (s *Service) Stats(ctx context.Context) (Stats, error) {
+	posts, err := s.store.List(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{
+		TotalPosts:  len(posts),
+		PostsPerTag: make(map[string]int),
+	}
+	for _, post := range posts {
+		for _, tag := range post.Tags {
+			stats.PostsPerTag[tag]++
+		}
+		stats.StoreSizeBytes += proto.Size(post)
+	}
+
+	return stats, nil
+}
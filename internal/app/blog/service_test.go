@@ -1,18 +1,30 @@
 package blog
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
 	"testing"
 
+	"grpc-blog/internal/infra/logging"
 	"grpc-blog/proto/blogpb"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func newTestService(t *testing.T) *Service {
 	t.Helper()
 
 	logger := zaptest.NewLogger(t)
-	return NewService(logger)
+	return NewService(logger, NewMemoryStore())
 }
 
 func TestNewService(t *testing.T) {
@@ -20,7 +32,7 @@ func TestNewService(t *testing.T) {
 	if svc == nil {
 		t.Fatal("expected service to be non-nil")
 	}
-	if len(svc.posts) != 0 {
+	if posts, _ := svc.Snapshot(context.Background()); len(posts) != 0 {
 		t.Fatal("expected empty post store")
 	}
 }
@@ -34,7 +46,7 @@ func TestCreate(t *testing.T) {
 		Author:  "author",
 	}
 
-	created, err := svc.CreatePost(post)
+	created, err := svc.CreatePost(context.Background(), post)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -43,7 +55,7 @@ func TestCreate(t *testing.T) {
 		t.Fatal("expected PostId to be set")
 	}
 
-	if svc.posts[created.PostId] == nil {
+	if _, err := svc.store.Get(context.Background(), created.PostId); err != nil {
 		t.Fatal("post not stored")
 	}
 }
@@ -51,9 +63,9 @@ func TestCreate(t *testing.T) {
 func TestReadSuccess(t *testing.T) {
 	svc := newTestService(t)
 
-	post, _ := svc.CreatePost(&blogpb.BlogPost{Title: "read-test"})
+	post, _ := svc.CreatePost(context.Background(), &blogpb.BlogPost{Title: "read-test"})
 
-	read, err := svc.ReadPost(post.PostId)
+	read, err := svc.ReadPost(context.Background(), post.PostId)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -66,16 +78,81 @@ func TestReadSuccess(t *testing.T) {
 func TestReadNotFound(t *testing.T) {
 	svc := newTestService(t)
 
-	_, err := svc.ReadPost("non-existent-id")
+	_, err := svc.ReadPost(context.Background(), "non-existent-id")
 	if err == nil {
 		t.Fatal("expected error for missing post")
 	}
 }
 
+func TestReadPage(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		svc.CreatePost(ctx, &blogpb.BlogPost{Title: "post"})
+	}
+
+	var ids []string
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("expected three pages of two posts")
+		}
+
+		page, err := svc.ReadPage(ctx, 2, token)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, post := range page.Posts {
+			ids = append(ids, post.PostId)
+		}
+
+		// A post created mid-listing sorts somewhere; it must not repeat others.
+		if pages == 0 {
+			svc.CreatePost(ctx, &blogpb.BlogPost{Title: "late"})
+		}
+
+		if token = page.NextPageToken; token == "" {
+			break
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, id := range ids {
+		if seen[id] {
+			t.Fatalf("post %s returned twice", id)
+		}
+		seen[id] = true
+		if i > 0 && ids[i-1] > id {
+			t.Fatalf("expected posts ordered by ID, got %v", ids)
+		}
+	}
+	if len(ids) < 5 {
+		t.Fatalf("expected every post, got %d", len(ids))
+	}
+
+	all, err := svc.ReadPage(ctx, 0, "")
+	if err != nil || len(all.Posts) != 6 || all.NextPageToken != "" {
+		t.Fatalf("expected all six posts in one page, got %d posts, token %q, err %v", len(all.Posts), all.NextPageToken, err)
+	}
+
+	// Paging resumes after a token whose post was deleted in between.
+	first, _ := svc.ReadPage(ctx, 2, "")
+	svc.DeletePost(ctx, first.Posts[1].PostId)
+	next, err := svc.ReadPage(ctx, 2, first.NextPageToken)
+	if err != nil || len(next.Posts) != 2 || next.Posts[0].PostId != all.Posts[2].PostId {
+		t.Fatalf("expected the page after the deleted post, got %v, %v", next.Posts, err)
+	}
+
+	if _, err := svc.ReadPage(ctx, 2, "not a token!"); err != ErrInvalidPageToken {
+		t.Fatalf("expected ErrInvalidPageToken, got %v", err)
+	}
+}
+
 func TestUpdateSuccess(t *testing.T) {
 	svc := newTestService(t)
 
-	created, _ := svc.CreatePost(&blogpb.BlogPost{Title: "old"})
+	created, _ := svc.CreatePost(context.Background(), &blogpb.BlogPost{Title: "old"})
 
 	updatedPost := &blogpb.BlogPost{
 		Title:   "new",
@@ -83,7 +160,7 @@ func TestUpdateSuccess(t *testing.T) {
 		Author:  "author",
 	}
 
-	updated, err := svc.UpdatePost(created.PostId, updatedPost)
+	updated, err := svc.UpdatePost(context.Background(), created.PostId, updatedPost)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -97,26 +174,283 @@ func TestUpdateSuccess(t *testing.T) {
 	}
 }
 
+func TestUpdateFields(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	created, _ := svc.CreatePost(ctx, &blogpb.BlogPost{
+		Title:           "old",
+		Content:         "body",
+		Tags:            []string{"go"},
+		PublicationDate: timestamppb.Now(),
+	})
+
+	updated, err := svc.UpdatePost(ctx, created.PostId, &blogpb.BlogPost{Title: "new", Content: "ignored"}, "title", "tags")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Title != "new" || updated.Content != "body" || updated.PublicationDate == nil {
+		t.Fatalf("expected only the named fields to change, got %v", updated)
+	}
+	if len(updated.Tags) != 0 {
+		t.Fatalf("expected a named unset field to be cleared, got %v", updated.Tags)
+	}
+
+	if _, err := svc.UpdatePost(ctx, created.PostId, &blogpb.BlogPost{}, "post_id"); !errors.Is(err, ErrInvalidUpdateMask) {
+		t.Fatalf("expected ErrInvalidUpdateMask, got %v", err)
+	}
+}
+
+func TestConcurrentFieldUpdates(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	created, _ := svc.CreatePost(ctx, &blogpb.BlogPost{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			svc.UpdatePost(ctx, created.PostId, &blogpb.BlogPost{Title: "title"}, "title")
+		}()
+		go func() {
+			defer wg.Done()
+			svc.UpdatePost(ctx, created.PostId, &blogpb.BlogPost{Author: "author"}, "author")
+		}()
+	}
+	wg.Wait()
+
+	if got, _ := svc.ReadPost(ctx, created.PostId); got.Title != "title" || got.Author != "author" {
+		t.Fatalf("expected both fields to be kept, got %v", got)
+	}
+}
+
 func TestUpdateNotFound(t *testing.T) {
 	svc := newTestService(t)
 
-	_, err := svc.UpdatePost("missing-id", &blogpb.BlogPost{})
+	_, err := svc.UpdatePost(context.Background(), "missing-id", &blogpb.BlogPost{})
 	if err == nil {
 		t.Fatal("expected error for updating missing post")
 	}
 }
 
+func TestSlugs(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	first, err := svc.CreatePost(ctx, &blogpb.BlogPost{Title: "a", Slug: "hello-world"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := svc.CreatePost(ctx, &blogpb.BlogPost{Title: "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.CreatePost(ctx, &blogpb.BlogPost{Slug: "hello-world"}); !errors.Is(err, ErrSlugTaken) {
+		t.Fatalf("expected ErrSlugTaken on create, got %v", err)
+	}
+	if _, err := svc.UpdatePost(ctx, second.PostId, &blogpb.BlogPost{Slug: "hello-world"}); !errors.Is(err, ErrSlugTaken) {
+		t.Fatalf("expected ErrSlugTaken on update, got %v", err)
+	}
+
+	// A post keeps its own slug, and a slug is free once its post drops it.
+	if _, err := svc.UpdatePost(ctx, first.PostId, &blogpb.BlogPost{Title: "c", Slug: "hello-world"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.UpdatePost(ctx, first.PostId, &blogpb.BlogPost{Title: "c"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.UpdatePost(ctx, second.PostId, &blogpb.BlogPost{Slug: "hello-world"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.DeletePost(ctx, second.PostId); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.CreatePost(ctx, &blogpb.BlogPost{Slug: "hello-world"}); err != nil {
+		t.Fatalf("expected the slug of a deleted post to be free, got %v", err)
+	}
+
+	for _, slug := range []string{"Hello", "hello world", "-a", "a-", "a--b", "a/b", strings.Repeat("a", 101)} {
+		if _, err := svc.CreatePost(ctx, &blogpb.BlogPost{Slug: slug}); !errors.Is(err, ErrInvalidSlug) {
+			t.Errorf("slug %q: expected ErrInvalidSlug, got %v", slug, err)
+		}
+	}
+	if _, err := svc.CreatePost(ctx, &blogpb.BlogPost{Slug: "ünïcode-2024"}); err != nil {
+		t.Fatalf("expected a lower case unicode slug to be valid, got %v", err)
+	}
+}
+
+func TestConcurrentSlugs(t *testing.T) {
+	svc := newTestService(t)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.CreatePost(context.Background(), &blogpb.BlogPost{Slug: "race"}); err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Fatalf("expected exactly one post to get the slug, got %d", created)
+	}
+}
+
+func TestContentFormats(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		format  blogpb.ContentFormat
+		content string
+		html    string
+	}{
+		{blogpb.ContentFormat_CONTENT_FORMAT_UNSPECIFIED, "a < b\nc\n\nd", "<p>a &lt; b<br>\nc</p>\n<p>d</p>\n"},
+		{blogpb.ContentFormat_CONTENT_FORMAT_PLAIN, "<b>hi</b>", "<p>&lt;b&gt;hi&lt;/b&gt;</p>\n"},
+		{blogpb.ContentFormat_CONTENT_FORMAT_MARKDOWN, "# Hi\n\n~~old~~ **new**", "<h1>Hi</h1>\n<p><del>old</del> <strong>new</strong></p>\n"},
+		{blogpb.ContentFormat_CONTENT_FORMAT_MARKDOWN, "<em>ok</em><script>alert(1)</script>", "<p><em>ok</em></p>\n"},
+		{blogpb.ContentFormat_CONTENT_FORMAT_MARKDOWN, "[x](javascript:alert(1))", "<p>x</p>\n"},
+		{blogpb.ContentFormat_CONTENT_FORMAT_HTML, `<p onclick="x()">hi<script>alert(1)</script></p>`, "<p>hi</p>"},
+		{blogpb.ContentFormat_CONTENT_FORMAT_HTML, `<a href="javascript:alert(1)">a</a><img src="x.png" onerror="y()">`, `a<img src="x.png">`},
+	}
+	for _, tc := range cases {
+		post, err := svc.CreatePost(ctx, &blogpb.BlogPost{Content: tc.content, ContentFormat: tc.format, RenderedHtml: "<script>"})
+		if err != nil {
+			t.Fatalf("%v %q: unexpected error: %v", tc.format, tc.content, err)
+		}
+		if post.RenderedHtml != tc.html {
+			t.Errorf("%v %q: rendered %q, want %q", tc.format, tc.content, post.RenderedHtml, tc.html)
+		}
+		if tc.format == blogpb.ContentFormat_CONTENT_FORMAT_HTML && post.Content != tc.html {
+			t.Errorf("expected HTML content to be stored sanitized, got %q", post.Content)
+		}
+	}
+
+	// An update renders the new content; the cached HTML is read back.
+	post, _ := svc.CreatePost(ctx, &blogpb.BlogPost{Content: "*a*", ContentFormat: blogpb.ContentFormat_CONTENT_FORMAT_MARKDOWN})
+	if _, err := svc.UpdatePost(ctx, post.PostId, &blogpb.BlogPost{Content: "*b*"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := svc.ReadPost(ctx, post.PostId); got.RenderedHtml != "<p>*b*</p>\n" {
+		t.Fatalf("expected the update to render as plain text, got %q", got.RenderedHtml)
+	}
+
+	// Restored posts are rendered again rather than trusted.
+	stale := &blogpb.BlogPost{PostId: "a", Content: "x", RenderedHtml: "<script>alert(1)</script>"}
+	if err := svc.Restore(ctx, []*blogpb.BlogPost{stale}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := svc.ReadPost(ctx, "a"); got.RenderedHtml != "<p>x</p>\n" {
+		t.Fatalf("expected Restore to render the post, got %q", got.RenderedHtml)
+	}
+
+	if _, err := svc.CreatePost(ctx, &blogpb.BlogPost{ContentFormat: 42}); !errors.Is(err, ErrInvalidContentFormat) {
+		t.Fatalf("expected ErrInvalidContentFormat, got %v", err)
+	}
+	if _, err := svc.UpdatePost(ctx, "a", &blogpb.BlogPost{ContentFormat: 42}); !errors.Is(err, ErrInvalidContentFormat) {
+		t.Fatalf("expected ErrInvalidContentFormat, got %v", err)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	kept, _ := svc.CreatePost(ctx, &blogpb.BlogPost{Title: "kept", Slug: "kept"})
+	svc.CreatePost(ctx, &blogpb.BlogPost{Title: "also kept"})
+
+	snapshot, err := svc.Snapshot(ctx)
+	if err != nil || len(snapshot) != 2 || snapshot[0].PostId > snapshot[1].PostId {
+		t.Fatalf("expected 2 posts ordered by ID, got %v, %v", snapshot, err)
+	}
+
+	svc.DeletePost(ctx, kept.PostId)
+	svc.CreatePost(ctx, &blogpb.BlogPost{Title: "dropped"})
+
+	if err := svc.Restore(ctx, snapshot); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	posts, _ := svc.Snapshot(ctx)
+	if len(posts) != 2 {
+		t.Fatalf("expected the 2 snapshot posts, got %v", posts)
+	}
+	if got, err := svc.ReadPost(ctx, kept.PostId); err != nil || got.Slug != "kept" {
+		t.Fatalf("expected the deleted post back, got %v, %v", got, err)
+	}
+	if _, err := svc.CreatePost(ctx, &blogpb.BlogPost{Slug: "kept"}); !errors.Is(err, ErrSlugTaken) {
+		t.Fatalf("expected the restored slug to be taken, got %v", err)
+	}
+
+	// The restored posts are copies.
+	snapshot[0].Title = "changed"
+	if got, _ := svc.ReadPost(ctx, snapshot[0].PostId); got.Title == "changed" {
+		t.Fatal("expected Restore to store copies")
+	}
+
+	for _, bad := range [][]*blogpb.BlogPost{
+		{{Title: "no id"}},
+		{{PostId: "a"}, {PostId: "a"}},
+		{{PostId: "a", Slug: "Bad"}},
+		{{PostId: "a", Slug: "same"}, {PostId: "b", Slug: "same"}},
+	} {
+		if err := svc.Restore(ctx, bad); err == nil {
+			t.Errorf("expected an error restoring %v", bad)
+		}
+	}
+	if posts, _ := svc.Snapshot(ctx); len(posts) != 2 {
+		t.Fatalf("expected a failed restore to change nothing, got %d posts", len(posts))
+	}
+}
+
+func TestConcurrentRestore(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	small := []*blogpb.BlogPost{{PostId: "a"}}
+	large := []*blogpb.BlogPost{{PostId: "a"}, {PostId: "b"}, {PostId: "c"}}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			svc.Restore(ctx, small)
+			svc.Restore(ctx, large)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		posts, err := svc.Snapshot(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n := len(posts); n != 0 && n != len(small) && n != len(large) {
+			t.Fatalf("expected whole sets of posts, got %d", n)
+		}
+	}
+	wg.Wait()
+}
+
 func TestDeleteSuccess(t *testing.T) {
 	svc := newTestService(t)
 
-	created, _ := svc.CreatePost(&blogpb.BlogPost{Title: "delete-test"})
+	created, _ := svc.CreatePost(context.Background(), &blogpb.BlogPost{Title: "delete-test"})
 
-	err := svc.DeletePost(created.PostId)
+	err := svc.DeletePost(context.Background(), created.PostId)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if _, exists := svc.posts[created.PostId]; exists {
+	if _, err := svc.store.Get(context.Background(), created.PostId); err != ErrNotFound {
 		t.Fatal("post should be deleted")
 	}
 }
@@ -124,8 +458,228 @@ func TestDeleteSuccess(t *testing.T) {
 func TestDeleteNotFound(t *testing.T) {
 	svc := newTestService(t)
 
-	err := svc.DeletePost("missing-id")
+	err := svc.DeletePost(context.Background(), "missing-id")
 	if err == nil {
 		t.Fatal("expected error for deleting missing post")
 	}
 }
+
+func TestHealthCheck(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("expected healthy store: %v", err)
+	}
+}
+
+func TestStats(t *testing.T) {
+	svc := newTestService(t)
+
+	svc.CreatePost(context.Background(), &blogpb.BlogPost{Title: "a", Tags: []string{"go", "grpc"}})
+	svc.CreatePost(context.Background(), &blogpb.BlogPost{Title: "b", Tags: []string{"go"}})
+
+	stats, err := svc.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.TotalPosts != 2 {
+		t.Fatalf("expected 2 posts, got %d", stats.TotalPosts)
+	}
+
+	if stats.PostsPerTag["go"] != 2 || stats.PostsPerTag["grpc"] != 1 {
+		t.Fatalf("unexpected tag counts: %v", stats.PostsPerTag)
+	}
+
+	if stats.StoreSizeBytes == 0 {
+		t.Fatal("expected non-zero store size")
+	}
+}
+
+func TestDomainSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	svc := newTestService(t)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	created, _ := svc.CreatePost(ctx, &blogpb.BlogPost{Title: "traced"})
+	svc.ReadPost(ctx, "missing-id")
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	create := spans[0]
+	if create.Name() != "blog.CreatePost" {
+		t.Fatalf("unexpected span name %q", create.Name())
+	}
+	if create.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("domain span should be a child of the request span")
+	}
+
+	found := false
+	for _, attr := range create.Attributes() {
+		if attr.Key == "blog.post_id" && attr.Value.AsString() == created.PostId {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected post_id attribute on span")
+	}
+
+	if read := spans[1]; read.Status().Code != codes.Error {
+		t.Fatal("expected failed read to mark span as error")
+	}
+}
+
+func TestDomainLogsUseContextLogger(t *testing.T) {
+	svc := newTestService(t)
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := logging.WithContext(context.Background(), zap.New(core).With(zap.String("request_id", "req-1")))
+
+	svc.CreatePost(ctx, &blogpb.BlogPost{Title: "correlated"})
+
+	if logs.FilterMessage("post created").FilterField(zap.String("request_id", "req-1")).Len() != 1 {
+		t.Fatal("expected domain log to carry the request id")
+	}
+}
+
+func TestCallerMutationsDoNotReachStore(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	input := &blogpb.BlogPost{Title: "original", Tags: []string{"go"}}
+	created, _ := svc.CreatePost(ctx, input)
+
+	if input.PostId != "" {
+		t.Fatal("CreatePost must not modify the caller's post")
+	}
+
+	// Mutate everything the caller holds: the request and every result.
+	input.Title = "changed input"
+	input.Tags[0] = "changed"
+	created.Title = "changed result"
+
+	read, _ := svc.ReadPost(ctx, created.PostId)
+	read.Tags = append(read.Tags, "extra")
+
+	page, _ := svc.ReadPage(ctx, 0, "")
+	page.Posts[0].Title = "changed list"
+
+	update := &blogpb.BlogPost{Title: "updated", Tags: []string{"grpc"}}
+	updated, _ := svc.UpdatePost(ctx, created.PostId, update)
+	update.Tags[0] = "changed update"
+	updated.Title = "changed update result"
+
+	got, err := svc.ReadPost(ctx, created.PostId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Title != "updated" || len(got.Tags) != 1 || got.Tags[0] != "grpc" {
+		t.Fatalf("stored post was modified through a caller reference: %v", got)
+	}
+}
+
+// TestConcurrentReadModify is meant to run under -race: readers mutate the
+// posts they receive while writers replace them.
+func TestConcurrentReadModify(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	created, _ := svc.CreatePost(ctx, &blogpb.BlogPost{Title: "shared", Tags: []string{"go"}})
+	request := &blogpb.BlogPost{Title: "request", Tags: []string{"reused"}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(4)
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				post, err := svc.ReadPost(ctx, created.PostId)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				post.Title = "reader"
+				post.Tags = append(post.Tags, "mutated")
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				page, _ := svc.ReadPage(ctx, 0, "")
+				for _, post := range page.Posts {
+					post.Tags = nil
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				// A client reusing one request message across calls.
+				if _, err := svc.UpdatePost(ctx, created.PostId, request); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, err := svc.Stats(ctx); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, _ := svc.ReadPost(ctx, created.PostId)
+	if got.Title != "request" || len(got.Tags) != 1 || got.Tags[0] != "reused" {
+		t.Fatalf("stored post corrupted by concurrent callers: %v", got)
+	}
+}
+
+func TestConcurrentCreateDelete(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			post := &blogpb.BlogPost{Title: "tmp", Tags: []string{"a"}}
+			for j := 0; j < 20; j++ {
+				created, err := svc.CreatePost(ctx, post)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				post.Tags[0] = "b" // reuse the request with new content
+				if err := svc.DeletePost(ctx, created.PostId); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stats, _ := svc.Stats(ctx); stats.TotalPosts != 0 {
+		t.Fatalf("expected an empty store, got %d posts", stats.TotalPosts)
+	}
+}
@@ -0,0 +1,198 @@
+package blog
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"grpc-blog/proto/blogpb"
+)
+
+// Store persists blog posts for the Service.
+//
+// Implementations must be safe for concurrent use and return ErrNotFound
+// for unknown post IDs. They keep non-empty slugs unique: a write giving a
+// post the slug of another fails with ErrSlugTaken, checked atomically
+// with the write. The Service owns the posts it passes in and receives; a
+// Store may keep the pointers.
+type Store interface {
+	// Insert adds post under post.PostId.
+	Insert(ctx context.Context, post *blogpb.BlogPost) error
+	// Get returns the post stored under id.
+	Get(ctx context.Context, id string) (*blogpb.BlogPost, error)
+	// List returns every stored post, as of one instant, in no particular
+	// order.
+	List(ctx context.Context) ([]*blogpb.BlogPost, error)
+	// ListAfter returns up to limit posts, or all of them for 0, with a
+	// PostId greater than after, ordered by PostId. Its cost should depend
+	// on limit, not on the number of stored posts.
+	ListAfter(ctx context.Context, after string, limit int) ([]*blogpb.BlogPost, error)
+	// Update replaces the post stored under id with the result of update,
+	// called with the current post, and returns the new post. No other
+	// write to the store runs while update does, so update must not call
+	// the store. Nothing changes if update fails.
+	Update(ctx context.Context, id string, update func(current *blogpb.BlogPost) (*blogpb.BlogPost, error)) (*blogpb.BlogPost, error)
+	// Delete removes the post stored under id.
+	Delete(ctx context.Context, id string) error
+	// ReplaceAll replaces every stored post with posts in one step: no
+	// reader sees a mix of the old and new posts.
+	ReplaceAll(ctx context.Context, posts []*blogpb.BlogPost) error
+	// Ping reports whether the store is usable.
+	Ping(ctx context.Context) error
+}
+
+// MemoryStore is a Store keeping posts in a map. Its contents are lost
+// when the process exits.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	posts map[string]*blogpb.BlogPost
+	// ids holds the keys of posts in order, for ListAfter. Inserts and
+	// deletes shift it, which is cheap next to copying posts out.
+	ids []string
+	// slugs maps each non-empty slug to the ID of the post having it.
+	slugs map[string]string
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		posts: make(map[string]*blogpb.BlogPost),
+		slugs: make(map[string]string),
+	}
+}
+
+func (m *MemoryStore) Insert(_ context.Context, post *blogpb.BlogPost) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.claimSlug(post); err != nil {
+		return err
+	}
+	if i, found := slices.BinarySearch(m.ids, post.PostId); !found {
+		m.ids = slices.Insert(m.ids, i, post.PostId)
+	}
+	m.posts[post.PostId] = post
+	return nil
+}
+
+// claimSlug indexes post.Slug for post, releasing the slug the post had
+// before. The caller holds m.mu for writing.
+func (m *MemoryStore) claimSlug(post *blogpb.BlogPost) error {
+	if owner, ok := m.slugs[post.Slug]; ok && owner != post.PostId {
+		return ErrSlugTaken
+	}
+	if old, ok := m.posts[post.PostId]; ok && old.Slug != "" {
+		delete(m.slugs, old.Slug)
+	}
+	if post.Slug != "" {
+		m.slugs[post.Slug] = post.PostId
+	}
+	return nil
+}
+
+func (m *MemoryStore) Get(_ context.Context, id string) (*blogpb.BlogPost, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	post, ok := m.posts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return post, nil
+}
+
+func (m *MemoryStore) List(_ context.Context) ([]*blogpb.BlogPost, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	posts := make([]*blogpb.BlogPost, 0, len(m.posts))
+	for _, post := range m.posts {
+		posts = append(posts, post)
+	}
+	return posts, nil
+}
+
+func (m *MemoryStore) ListAfter(_ context.Context, after string, limit int) ([]*blogpb.BlogPost, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	i, found := slices.BinarySearch(m.ids, after)
+	if found {
+		i++
+	}
+	ids := m.ids[i:]
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	posts := make([]*blogpb.BlogPost, len(ids))
+	for j, id := range ids {
+		posts[j] = m.posts[id]
+	}
+	return posts, nil
+}
+
+func (m *MemoryStore) Update(_ context.Context, id string, update func(*blogpb.BlogPost) (*blogpb.BlogPost, error)) (*blogpb.BlogPost, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.posts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	post, err := update(current)
+	if err != nil {
+		return nil, err
+	}
+	post.PostId = id
+	if err := m.claimSlug(post); err != nil {
+		return nil, err
+	}
+	m.posts[id] = post
+	return post, nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	post, ok := m.posts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if post.Slug != "" {
+		delete(m.slugs, post.Slug)
+	}
+	if i, found := slices.BinarySearch(m.ids, id); found {
+		m.ids = slices.Delete(m.ids, i, i+1)
+	}
+	delete(m.posts, id)
+	return nil
+}
+
+func (m *MemoryStore) ReplaceAll(_ context.Context, posts []*blogpb.BlogPost) error {
+	next := make(map[string]*blogpb.BlogPost, len(posts))
+	slugs := make(map[string]string)
+	for _, post := range posts {
+		if post.Slug != "" {
+			if _, ok := slugs[post.Slug]; ok {
+				return ErrSlugTaken
+			}
+			slugs[post.Slug] = post.PostId
+		}
+		next[post.PostId] = post
+	}
+	ids := slices.Sorted(maps.Keys(next))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.posts, m.ids, m.slugs = next, ids, slugs
+	return nil
+}
+
+// Ping always succeeds once the store is constructed.
+func (m *MemoryStore) Ping(_ context.Context) error {
+	return nil
+}
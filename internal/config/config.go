@@ -0,0 +1,373 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+
+	"grpc-blog/internal/app/backup"
+	"grpc-blog/internal/infra/health"
+	"grpc-blog/internal/infra/logging"
+	"grpc-blog/internal/infra/tracing"
+)
+
+// EnvPrefix prefixes every environment variable read by Load.
+const EnvPrefix = "BLOG_"
+
+const redacted = "[REDACTED]"
+
+// Config is the typed server configuration.
+//
+// Values are resolved by Load with the following precedence, highest first:
+// command-line flags, BLOG_* environment variables, the YAML file, defaults.
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Gateway  GatewayConfig  `yaml:"gateway"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	Admin    AdminConfig    `yaml:"admin"`
+	Log      logging.Config `yaml:"log"`
+	Tracing  tracing.Config `yaml:"tracing"`
+	Health   health.Config  `yaml:"health"`
+	Shutdown ShutdownConfig `yaml:"shutdown"`
+	Backup   backup.Config  `yaml:"backup"`
+}
+
+// ServerConfig configures the gRPC listener.
+type ServerConfig struct {
+	Addr string `yaml:"addr"`
+	// Reflection registers the gRPC server reflection service so generic
+	// tools can discover and call the API.
+	Reflection bool `yaml:"reflection"`
+	// Web serves gRPC-Web and Connect alongside native gRPC on Addr.
+	Web WebConfig `yaml:"web"`
+}
+
+// WebConfig configures browser access to the gRPC listener.
+type WebConfig struct {
+	Enabled bool       `yaml:"enabled"`
+	CORS    CORSConfig `yaml:"cors"`
+}
+
+// CORSConfig controls which browser origins may call the web handler.
+type CORSConfig struct {
+	// AllowedOrigins lists origins such as https://app.example.com; "*"
+	// allows any origin. Empty disables cross-origin requests.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	// AllowCredentials lets browsers send cookies and HTTP auth.
+	AllowCredentials bool `yaml:"allow_credentials"`
+	// MaxAge is how long browsers may cache preflight responses.
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// GatewayConfig configures the HTTP/JSON REST gateway.
+type GatewayConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+}
+
+// MetricsConfig configures the HTTP listener serving Prometheus metrics.
+type MetricsConfig struct {
+	Addr string `yaml:"addr"`
+}
+
+// AdminConfig configures the admin listener, which serves /admin/log/level
+// and AdminService to callers presenting the admin token. It is off while
+// Addr is empty.
+type AdminConfig struct {
+	Addr string `yaml:"addr"`
+	// Token is the bearer token every admin request must carry.
+	Token string `yaml:"token"`
+}
+
+// ShutdownConfig bounds how long the server drains before stopping hard.
+type ShutdownConfig struct {
+	// Timeout is the drain deadline shared by all components; when it
+	// expires in-flight requests are cancelled.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Default returns the configuration used when no other source is given.
+func Default() *Config {
+	return &Config{
+		Server:   ServerConfig{Addr: ":50051", Web: WebConfig{CORS: CORSConfig{MaxAge: 2 * time.Hour}}},
+		Gateway:  GatewayConfig{Enabled: true, Addr: ":8080"},
+		Metrics:  MetricsConfig{Addr: ":9090"},
+		Log:      logging.DefaultConfig(),
+		Tracing:  tracing.DefaultConfig("grpc-blog-server"),
+		Health:   health.DefaultConfig(),
+		Shutdown: ShutdownConfig{Timeout: 30 * time.Second},
+	}
+}
+
+// secretFlags lists settings whose values must never be logged.
+var secretFlags = map[string]bool{
+	"tracing.headers": true,
+	"admin.token":     true,
+}
+
+// bind registers one flag per setting on fs, each writing into c.
+//
+// The same binding drives flags, environment variables and the
+// effective-config dump, so every setting is named exactly once.
+func bind(fs *flag.FlagSet, c *Config) {
+	fs.StringVar(&c.Server.Addr, "server.addr", c.Server.Addr, "gRPC listen address")
+	fs.BoolVar(&c.Server.Reflection, "server.reflection", c.Server.Reflection, "enable gRPC server reflection")
+	fs.BoolVar(&c.Server.Web.Enabled, "server.web.enabled", c.Server.Web.Enabled, "serve gRPC-Web and Connect on the gRPC listener")
+	fs.Var((*listValue)(&c.Server.Web.CORS.AllowedOrigins), "server.web.cors.allowed-origins", "comma-separated browser origins allowed to call the server; * allows any")
+	fs.BoolVar(&c.Server.Web.CORS.AllowCredentials, "server.web.cors.allow-credentials", c.Server.Web.CORS.AllowCredentials, "allow cross-origin requests with cookies or HTTP auth")
+	fs.DurationVar(&c.Server.Web.CORS.MaxAge, "server.web.cors.max-age", c.Server.Web.CORS.MaxAge, "how long browsers may cache CORS preflight responses")
+	fs.BoolVar(&c.Gateway.Enabled, "gateway.enabled", c.Gateway.Enabled, "serve the HTTP/JSON REST gateway")
+	fs.StringVar(&c.Gateway.Addr, "gateway.addr", c.Gateway.Addr, "REST gateway listen address")
+	fs.StringVar(&c.Metrics.Addr, "metrics.addr", c.Metrics.Addr, "HTTP listen address for /metrics")
+	fs.StringVar(&c.Admin.Addr, "admin.addr", c.Admin.Addr, "HTTP/2 listen address for /admin/log/level and AdminService; empty disables it")
+	fs.StringVar(&c.Admin.Token, "admin.token", c.Admin.Token, "bearer token required on the admin listener (prefer env BLOG_ADMIN_TOKEN)")
+	fs.StringVar(&c.Log.Level, "log.level", c.Log.Level, "initial minimum log level (debug, info, warn, error)")
+	fs.StringVar(&c.Log.Encoding, "log.encoding", c.Log.Encoding, "log encoding (json, console)")
+	fs.IntVar(&c.Log.Sampling.Initial, "log.sampling.initial", c.Log.Sampling.Initial, "identical entries logged per second before sampling; 0 disables sampling")
+	fs.IntVar(&c.Log.Sampling.Thereafter, "log.sampling.thereafter", c.Log.Sampling.Thereafter, "log every Nth identical entry once sampling kicks in")
+	fs.StringVar(&c.Log.File.Path, "log.file.path", c.Log.File.Path, "write logs to this file instead of stderr")
+	fs.IntVar(&c.Log.File.MaxSizeMB, "log.file.max-size-mb", c.Log.File.MaxSizeMB, "rotate the log file after this many megabytes")
+	fs.IntVar(&c.Log.File.MaxBackups, "log.file.max-backups", c.Log.File.MaxBackups, "rotated log files to keep; 0 keeps all")
+	fs.IntVar(&c.Log.File.MaxAgeDays, "log.file.max-age-days", c.Log.File.MaxAgeDays, "days to keep rotated log files; 0 keeps them forever")
+	fs.BoolVar(&c.Log.File.Compress, "log.file.compress", c.Log.File.Compress, "gzip rotated log files")
+	fs.BoolVar(&c.Log.Payload.Enabled, "log.payload.enabled", c.Log.Payload.Enabled, "log RPC payloads at debug level")
+	fs.Float64Var(&c.Log.Payload.SampleRate, "log.payload.sample-rate", c.Log.Payload.SampleRate, "fraction of RPC payloads logged (0..1)")
+	fs.Var((*listValue)(&c.Log.Payload.Redact), "log.payload.redact", "comma-separated protobuf field names masked in logged payloads")
+	fs.StringVar(&c.Tracing.Exporter, "tracing.exporter", c.Tracing.Exporter, "span exporter (stdout, otlp-grpc, otlp-http, none)")
+	fs.StringVar(&c.Tracing.Endpoint, "tracing.endpoint", c.Tracing.Endpoint, "OTLP collector host:port")
+	fs.BoolVar(&c.Tracing.Insecure, "tracing.insecure", c.Tracing.Insecure, "disable TLS towards the OTLP collector")
+	fs.Var((*mapValue)(&c.Tracing.Headers), "tracing.headers", "OTLP export headers as key=value pairs separated by commas")
+	fs.StringVar(&c.Tracing.ServiceName, "tracing.service-name", c.Tracing.ServiceName, "service.name resource attribute")
+	fs.StringVar(&c.Tracing.ServiceVersion, "tracing.service-version", c.Tracing.ServiceVersion, "service.version resource attribute")
+	fs.Float64Var(&c.Tracing.SampleRatio, "tracing.sample-ratio", c.Tracing.SampleRatio, "fraction of root traces sampled (0..1)")
+	fs.DurationVar(&c.Health.Interval, "health.interval", c.Health.Interval, "interval between dependency health checks")
+	fs.DurationVar(&c.Health.Timeout, "health.timeout", c.Health.Timeout, "timeout of a single dependency health check")
+	fs.DurationVar(&c.Shutdown.Timeout, "shutdown.timeout", c.Shutdown.Timeout, "drain deadline on shutdown, after which servers are stopped forcibly")
+	fs.StringVar(&c.Backup.Dir, "backup.dir", c.Backup.Dir, "directory for backups taken through AdminService; empty disables AdminService")
+}
+
+// Load resolves the configuration from args, the environment and an optional
+// YAML file named by -config or BLOG_CONFIG, then validates it.
+//
+// getenv is usually os.Getenv; tests pass a stub.
+func Load(args []string, getenv func(string) string) (*Config, error) {
+	// Parse flags into a scratch config only to learn which ones were set;
+	// they are replayed last so they take precedence over the other sources.
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	path := fs.String("config", getenv(EnvPrefix+"CONFIG"), "path to a YAML config file")
+	bind(fs, Default())
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if *path != "" {
+		if err := cfg.loadFile(*path); err != nil {
+			return nil, err
+		}
+	}
+
+	target := flag.NewFlagSet("", flag.ContinueOnError)
+	bind(target, cfg)
+
+	var errs []error
+	target.VisitAll(func(f *flag.Flag) {
+		name := EnvName(f.Name)
+		if v := getenv(name); v != "" {
+			if err := f.Value.Set(v); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	})
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			return
+		}
+		if err := target.Set(f.Name, f.Value.String()); err != nil {
+			errs = append(errs, fmt.Errorf("-%s: %w", f.Name, err))
+		}
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// EnvName returns the environment variable overriding the named setting,
+// e.g. "tracing.sample-ratio" becomes BLOG_TRACING_SAMPLE_RATIO.
+func EnvName(setting string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(setting))
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, _, err := net.SplitHostPort(c.Server.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("server.addr: %w", err))
+	}
+	if c.Server.Web.CORS.AllowCredentials && slices.Contains(c.Server.Web.CORS.AllowedOrigins, "*") {
+		errs = append(errs, errors.New("server.web.cors: credentials cannot be allowed for origin *"))
+	}
+	if c.Server.Web.CORS.MaxAge < 0 {
+		errs = append(errs, errors.New("server.web.cors.max-age: must not be negative"))
+	}
+	if _, _, err := net.SplitHostPort(c.Gateway.Addr); c.Gateway.Enabled && err != nil {
+		errs = append(errs, fmt.Errorf("gateway.addr: %w", err))
+	}
+	if _, _, err := net.SplitHostPort(c.Metrics.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("metrics.addr: %w", err))
+	}
+	if c.Admin.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Admin.Addr); err != nil {
+			errs = append(errs, fmt.Errorf("admin.addr: %w", err))
+		}
+		if c.Admin.Token == "" {
+			errs = append(errs, errors.New("admin.token: required with admin.addr"))
+		}
+	}
+	if c.Backup.Dir != "" && c.Admin.Addr == "" {
+		errs = append(errs, errors.New("backup.dir: requires admin.addr, which serves AdminService"))
+	}
+
+	if _, err := zapcore.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+	switch c.Log.Encoding {
+	case logging.EncodingJSON, logging.EncodingConsole:
+	default:
+		errs = append(errs, fmt.Errorf("log.encoding: unknown encoding %q", c.Log.Encoding))
+	}
+	if c.Log.Sampling.Initial < 0 || c.Log.Sampling.Thereafter < 0 {
+		errs = append(errs, errors.New("log.sampling: values must not be negative"))
+	}
+	if c.Log.Payload.SampleRate < 0 || c.Log.Payload.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("log.payload.sample-rate: %v is outside [0, 1]", c.Log.Payload.SampleRate))
+	}
+	if c.Log.File.MaxSizeMB < 0 || c.Log.File.MaxBackups < 0 || c.Log.File.MaxAgeDays < 0 {
+		errs = append(errs, errors.New("log.file: rotation limits must not be negative"))
+	}
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterStdout, tracing.ExporterOTLPGRPC, tracing.ExporterOTLPHTTP, tracing.ExporterNone:
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter: unknown exporter %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample-ratio: %v is outside [0, 1]", c.Tracing.SampleRatio))
+	}
+	if c.Tracing.ServiceName == "" {
+		errs = append(errs, errors.New("tracing.service-name: must not be empty"))
+	}
+
+	if c.Health.Interval <= 0 {
+		errs = append(errs, errors.New("health.interval: must be positive"))
+	}
+	if c.Health.Timeout <= 0 {
+		errs = append(errs, errors.New("health.timeout: must be positive"))
+	}
+	if c.Shutdown.Timeout <= 0 {
+		errs = append(errs, errors.New("shutdown.timeout: must be positive"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// MarshalLogObject dumps every setting with secrets redacted, so the
+// effective configuration can be logged on startup with zap.Object.
+func (c *Config) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	bind(fs, c)
+
+	fs.VisitAll(func(f *flag.Flag) {
+		value := f.Value.String()
+		if secretFlags[f.Name] && value != "" {
+			value = redacted
+		}
+		enc.AddString(f.Name, value)
+	})
+	return nil
+}
+
+// mapValue is a flag.Value for comma-separated key=value pairs.
+type mapValue map[string]string
+
+func (m *mapValue) String() string {
+	if m == nil || len(*m) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(*m))
+	for k, v := range *m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m *mapValue) Set(s string) error {
+	out := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return fmt.Errorf("invalid pair %s", strconv.Quote(pair))
+		}
+		out[k] = strings.TrimSpace(v)
+	}
+	*m = out
+	return nil
+}
+
+// listValue is a flag.Value for comma-separated lists.
+type listValue []string
+
+func (l *listValue) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *listValue) Set(s string) error {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	*l = out
+	return nil
+}
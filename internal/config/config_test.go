@@ -0,0 +1,219 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load(nil, env(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Server.Addr != ":50051" {
+		t.Fatalf("unexpected default addr %q", cfg.Server.Addr)
+	}
+	if cfg.Log.Level != "info" {
+		t.Fatalf("unexpected default level %q", cfg.Log.Level)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, `
+server:
+  addr: ":6000"
+log:
+  level: debug
+tracing:
+  exporter: none
+  sample_ratio: 0.5
+`)
+
+	cfg, err := Load(
+		[]string{"-config", path, "-server.addr", ":8000"},
+		env(map[string]string{
+			"BLOG_SERVER_ADDR": ":7000",
+			"BLOG_LOG_LEVEL":   "warn",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Server.Addr != ":8000" {
+		t.Fatalf("flag should win over env and file, got %q", cfg.Server.Addr)
+	}
+	if cfg.Log.Level != "warn" {
+		t.Fatalf("env should win over file, got %q", cfg.Log.Level)
+	}
+	if cfg.Tracing.Exporter != "none" || cfg.Tracing.SampleRatio != 0.5 {
+		t.Fatalf("file should win over defaults, got %+v", cfg.Tracing)
+	}
+	if cfg.Metrics.Addr != ":9090" {
+		t.Fatalf("unset values should keep defaults, got %q", cfg.Metrics.Addr)
+	}
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	path := writeFile(t, "log:\n  level: error\n")
+
+	cfg, err := Load(nil, env(map[string]string{"BLOG_CONFIG": path}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Log.Level != "error" {
+		t.Fatalf("expected file from BLOG_CONFIG to be loaded, got %q", cfg.Log.Level)
+	}
+}
+
+func TestLoadUnknownField(t *testing.T) {
+	path := writeFile(t, "server:\n  port: 1\n")
+
+	if _, err := Load([]string{"-config", path}, env(nil)); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	_, err := Load(
+		[]string{"-log.level", "loud", "-log.encoding", "xml", "-tracing.sample-ratio", "2"},
+		env(map[string]string{"BLOG_TRACING_EXPORTER": "zipkin"}),
+	)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	for _, name := range []string{"log.level", "log.encoding", "tracing.sample-ratio", "tracing.exporter"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Fatalf("expected error to mention %s, got %v", name, err)
+		}
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("tracing.sample-ratio"); got != "BLOG_TRACING_SAMPLE_RATIO" {
+		t.Fatalf("unexpected env name %q", got)
+	}
+}
+
+func TestMarshalLogObjectRedactsSecrets(t *testing.T) {
+	cfg, err := Load(
+		[]string{"-tracing.headers", "authorization=Bearer secret"},
+		env(nil),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Tracing.Headers["authorization"] != "Bearer secret" {
+		t.Fatalf("headers not parsed: %v", cfg.Tracing.Headers)
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	if err := cfg.MarshalLogObject(enc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if enc.Fields["tracing.headers"] != redacted {
+		t.Fatalf("expected headers to be redacted, got %v", enc.Fields["tracing.headers"])
+	}
+	if enc.Fields["server.addr"] != ":50051" {
+		t.Fatalf("expected server.addr in dump, got %v", enc.Fields["server.addr"])
+	}
+}
+
+func TestLoadHeadersBlankKey(t *testing.T) {
+	_, err := Load([]string{"-tracing.headers", " =secret"}, env(nil))
+	if err == nil || !strings.Contains(err.Error(), "invalid pair") {
+		t.Fatalf("expected a blank header name to be rejected, got %v", err)
+	}
+}
+
+func TestLoadPayloadRedactList(t *testing.T) {
+	cfg, err := Load(nil, env(map[string]string{"BLOG_LOG_PAYLOAD_REDACT": "content, author"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	redact := cfg.Log.Payload.Redact
+	if len(redact) != 2 || redact[0] != "content" || redact[1] != "author" {
+		t.Fatalf("unexpected redact list %v", redact)
+	}
+}
+
+func TestLoadHealthDurations(t *testing.T) {
+	path := writeFile(t, "health:\n  interval: 30s\n")
+
+	cfg, err := Load([]string{"-config", path, "-health.timeout", "500ms"}, env(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Health.Interval != 30*time.Second || cfg.Health.Timeout != 500*time.Millisecond {
+		t.Fatalf("unexpected health config %+v", cfg.Health)
+	}
+}
+
+func TestLoadWebCORS(t *testing.T) {
+	path := writeFile(t, "server:\n  web:\n    enabled: true\n    cors:\n      allowed_origins: [https://app.example.com]\n")
+
+	cfg, err := Load([]string{"-config", path}, env(map[string]string{"BLOG_SERVER_WEB_CORS_MAX_AGE": "1m"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	web := cfg.Server.Web
+	if !web.Enabled || len(web.CORS.AllowedOrigins) != 1 || web.CORS.MaxAge != time.Minute {
+		t.Fatalf("unexpected web config %+v", web)
+	}
+
+	_, err = Load([]string{"-server.web.cors.allowed-origins", "*", "-server.web.cors.allow-credentials"}, env(nil))
+	if err == nil || !strings.Contains(err.Error(), "server.web.cors") {
+		t.Fatalf("expected wildcard with credentials to be rejected, got %v", err)
+	}
+}
+
+func TestLoadAdmin(t *testing.T) {
+	cfg, err := Load(
+		[]string{"-admin.addr", "127.0.0.1:9091", "-backup.dir", "/var/backups"},
+		env(map[string]string{"BLOG_ADMIN_TOKEN": "s3cret"}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Admin.Token != "s3cret" {
+		t.Fatalf("token not read from the environment: %+v", cfg.Admin)
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	cfg.MarshalLogObject(enc)
+	if enc.Fields["admin.token"] != redacted {
+		t.Fatalf("expected the token to be redacted, got %v", enc.Fields["admin.token"])
+	}
+
+	_, err = Load([]string{"-admin.addr", "127.0.0.1:9091"}, env(nil))
+	if err == nil || !strings.Contains(err.Error(), "admin.token") {
+		t.Fatalf("expected an admin listener without a token to be rejected, got %v", err)
+	}
+	_, err = Load([]string{"-backup.dir", "/var/backups"}, env(nil))
+	if err == nil || !strings.Contains(err.Error(), "backup.dir") {
+		t.Fatalf("expected backups without an admin listener to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"grpc-blog/internal/app/blog"
+	"grpc-blog/internal/config"
+	"grpc-blog/internal/infra/health"
+	"grpc-blog/internal/infra/metrics"
+	"grpc-blog/internal/infra/tracing"
+	grpctransport "grpc-blog/internal/transport/grpc"
+	httptransport "grpc-blog/internal/transport/http"
+	"grpc-blog/proto/blogpb"
+)
+
+func newTracerHook(cfg *config.Config) Hook {
+	var shutdown func(context.Context) error
+
+	return Hook{
+		Name: "tracer",
+		OnStart: func(context.Context) (err error) {
+			shutdown, err = tracing.InitTracer(cfg.Tracing)
+			return err
+		},
+		OnStop: func(ctx context.Context) error { return shutdown(ctx) },
+	}
+}
+
+func newStorageHook(store blog.Store) Hook {
+	return Hook{Name: "storage", OnStart: store.Ping}
+}
+
+// newMetricsHook serves /metrics.
+func newMetricsHook(cfg *config.Config, logger *zap.Logger, lc *Lifecycle, registry *prometheus.Registry) Hook {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(registry))
+
+	return httpHook(lc, logger, "metrics", &http.Server{Addr: cfg.Metrics.Addr, Handler: mux})
+}
+
+func newHealthHook(checker *health.Checker) Hook {
+	return Hook{
+		Name: "health",
+		OnStart: func(context.Context) error {
+			checker.Start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			checker.Shutdown()
+			return nil
+		},
+	}
+}
+
+// newUnaryInterceptors is the chain shared by the gRPC server, the
+// gRPC-Web/Connect handler and the REST gateway.
+func newUnaryInterceptors(cfg *config.Config, logger *zap.Logger, m *metrics.RPCMetrics) []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		grpctransport.UnaryRequestIDInterceptor(logger),
+		grpctransport.UnaryLoggingInterceptor(logger, cfg.Log.Payload),
+		grpctransport.UnaryMetricsInterceptor(m),
+		grpctransport.UnaryRecoveryInterceptor(logger, m),
+	}
+}
+
+// newGRPCServer registers BlogService, health and, if enabled, reflection.
+func newGRPCServer(
+	cfg *config.Config,
+	logger *zap.Logger,
+	m *metrics.RPCMetrics,
+	unary []grpc.UnaryServerInterceptor,
+	blogServer *grpctransport.BlogGRPCServer,
+	service *blog.Service,
+	checker *health.Checker,
+) *grpc.Server {
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(
+			grpctransport.StreamRequestIDInterceptor(logger),
+			grpctransport.StreamLoggingInterceptor(logger, cfg.Log.Payload),
+			grpctransport.StreamMetricsInterceptor(m),
+			grpctransport.StreamRecoveryInterceptor(logger, m),
+		),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	)
+
+	blogpb.RegisterBlogServiceServer(grpcServer, blogServer)
+
+	if cfg.Server.Reflection {
+		reflection.Register(grpcServer)
+	}
+
+	checker.Register(blogpb.BlogService_ServiceDesc.ServiceName, "storage", service.HealthCheck)
+	healthpb.RegisterHealthServer(grpcServer, checker.Server())
+
+	return grpcServer
+}
+
+// newGRPCHook serves grpcServer on the gRPC listener.
+//
+// With gRPC-Web enabled, net/http owns the listener and hands native gRPC
+// requests (HTTP/2, h2c) to grpcServer; browser protocols over HTTP/1.1 or
+// HTTP/2 go to the web handler.
+func newGRPCHook(
+	cfg *config.Config,
+	logger *zap.Logger,
+	lc *Lifecycle,
+	grpcServer *grpc.Server,
+	blogServer *grpctransport.BlogGRPCServer,
+	unary []grpc.UnaryServerInterceptor,
+) Hook {
+
+	var webServer *http.Server
+	if cfg.Server.Web.Enabled {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		protocols.SetUnencryptedHTTP2(true)
+
+		web := otelhttp.NewHandler(httptransport.NewWebHandler(blogServer, unary...), "blog-web")
+		webServer = &http.Server{
+			Handler:   httptransport.SplitGRPC(grpcServer, httptransport.CORS(cfg.Server.Web.CORS, web)),
+			Protocols: protocols,
+		}
+	}
+
+	return Hook{
+		Name: "grpc",
+		OnStart: func(context.Context) error {
+			lis, err := net.Listen("tcp", cfg.Server.Addr)
+			if err != nil {
+				return err
+			}
+
+			lc.Go("grpc", func() error {
+				if webServer != nil {
+					return ignoreClosed(webServer.Serve(lis))
+				}
+				return grpcServer.Serve(lis)
+			})
+
+			logger.Info("gRPC server started",
+				zap.String("addr", lis.Addr().String()),
+				zap.Bool("web", cfg.Server.Web.Enabled),
+			)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			var webErr error
+			if webServer != nil {
+				webErr = stopHTTP(ctx, webServer)
+			}
+			return errors.Join(webErr, stopGRPC(ctx, grpcServer))
+		},
+	}
+}
+
+// newGatewayHook serves the REST gateway, or does nothing when disabled.
+func newGatewayHook(
+	cfg *config.Config,
+	logger *zap.Logger,
+	lc *Lifecycle,
+	blogServer *grpctransport.BlogGRPCServer,
+	unary []grpc.UnaryServerInterceptor,
+) Hook {
+
+	if !cfg.Gateway.Enabled {
+		return Hook{Name: "gateway"}
+	}
+
+	return httpHook(lc, logger, "gateway", &http.Server{
+		Addr:    cfg.Gateway.Addr,
+		Handler: otelhttp.NewHandler(httptransport.NewGateway(blogServer, unary...), "blog-gateway"),
+	}, zap.String("openapi", "/openapi.json"), zap.String("docs", "/docs"))
+}
+
+// newAdminHook serves /admin/log/level and, when backups are enabled,
+// AdminService on the admin listener to callers with the admin token, or
+// does nothing without admin.addr. The listener has no reflection, so
+// AdminService stays out of sight of the public port.
+func newAdminHook(
+	cfg *config.Config,
+	logger *zap.Logger,
+	lc *Lifecycle,
+	adminServer *grpctransport.AdminGRPCServer,
+	unary []grpc.UnaryServerInterceptor,
+	level zap.AtomicLevel,
+) Hook {
+
+	if cfg.Admin.Addr == "" {
+		return Hook{Name: "admin"}
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	)
+	if cfg.Backup.Dir != "" {
+		blogpb.RegisterAdminServiceServer(grpcServer, adminServer)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/admin/log/level", level)
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	return httpHook(lc, logger, "admin", &http.Server{
+		Addr:      cfg.Admin.Addr,
+		Handler:   httptransport.RequireToken(cfg.Admin.Token, httptransport.SplitGRPC(grpcServer, mux)),
+		Protocols: protocols,
+	}, zap.String("log_level", "/admin/log/level"), zap.Bool("backups", cfg.Backup.Dir != ""))
+}
+
+// httpHook listens on srv.Addr when started and shuts srv down, forcibly
+// once the drain deadline passes, when stopped.
+func httpHook(lc *Lifecycle, logger *zap.Logger, name string, srv *http.Server, fields ...zap.Field) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			lis, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+
+			lc.Go(name, func() error { return ignoreClosed(srv.Serve(lis)) })
+
+			logger.Info(name+" server started", append([]zap.Field{zap.String("addr", lis.Addr().String())}, fields...)...)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error { return stopHTTP(ctx, srv) },
+	}
+}
+
+// stopGRPC waits for in-flight RPCs to finish, cancelling them once ctx is
+// done.
+func stopGRPC(ctx context.Context, srv *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// stopHTTP drains srv, closing remaining connections once ctx is done.
+func stopHTTP(ctx context.Context, srv *http.Server) error {
+	if err := srv.Shutdown(ctx); err != nil {
+		srv.Close()
+		return err
+	}
+	return nil
+}
+
+func ignoreClosed(err error) error {
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
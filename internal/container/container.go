@@ -1,21 +1,145 @@
 package container
 
 import (
+	"context"
+	"log"
+
 	"go.uber.org/dig"
+	"go.uber.org/zap"
 
+	"grpc-blog/internal/app/backup"
 	"grpc-blog/internal/app/blog"
+	"grpc-blog/internal/config"
+	"grpc-blog/internal/infra/health"
 	"grpc-blog/internal/infra/logging"
+	"grpc-blog/internal/infra/metrics"
+	grpctransport "grpc-blog/internal/transport/grpc"
 )
 
-func Build() (*dig.Container, error) {
-	c := dig.New()
+// ServerModules returns the modules wired by cmd/server, in order.
+func ServerModules(cfg *config.Config) []Module {
+	return []Module{
+		InfraModule(cfg),
+		StorageModule(),
+		AppModule(),
+		TransportModule(),
+	}
+}
+
+// InfraModule provides configuration, logging, tracing, metrics, health
+// checking and the Lifecycle.
+func InfraModule(cfg *config.Config) Module {
+	return Module{
+		Name: "infra",
+		Providers: []Provider{
+			Provide(func() *config.Config { return cfg }),
+			Provide(func(cfg *config.Config) logging.Config { return cfg.Log }),
+			Provide(logging.NewLevel),
+			Provide(logging.NewLogger),
+			Provide(func(cfg *config.Config) health.Config { return cfg.Health }),
+			Provide(health.NewChecker),
+			Provide(metrics.NewRegistry),
+			Provide(metrics.NewRPCMetrics),
+			Provide(func(cfg *config.Config, logger *zap.Logger) *Lifecycle {
+				return NewLifecycle(logger, cfg.Shutdown.Timeout)
+			}),
+			ProvideNamed("tracer", newTracerHook),
+			ProvideNamed("metrics", newMetricsHook),
+			ProvideNamed("health", newHealthHook),
+		},
+	}
+}
+
+// StorageModule provides the in-memory blog.Store. Tests replace it with a
+// module providing another blog.Store and "storage" Hook.
+func StorageModule() Module {
+	return Module{
+		Name: "storage",
+		Providers: []Provider{
+			Provide(func() blog.Store { return blog.NewMemoryStore() }),
+			ProvideNamed("storage", newStorageHook),
+		},
+	}
+}
 
-	if err := c.Provide(logging.NewLogger); err != nil {
-		return nil, err
+// AppModule provides the domain service and its backups.
+func AppModule() Module {
+	return Module{
+		Name: "app",
+		Providers: []Provider{
+			Provide(blog.NewService),
+			Provide(func(cfg *config.Config) backup.Config { return cfg.Backup }),
+			Provide(backup.NewManager),
+		},
 	}
-	if err := c.Provide(blog.NewService); err != nil {
-		return nil, err
+}
+
+// TransportModule provides the gRPC server, the gRPC-Web/Connect handler,
+// the REST gateway and the admin listener, sharing one interceptor chain.
+func TransportModule() Module {
+	return Module{
+		Name: "transport",
+		Providers: []Provider{
+			Provide(newUnaryInterceptors),
+			Provide(grpctransport.NewBlogGRPCServer),
+			Provide(grpctransport.NewAdminGRPCServer),
+			Provide(newGRPCServer),
+			ProvideNamed("grpc", newGRPCHook),
+			ProvideNamed("gateway", newGatewayHook),
+			ProvideNamed("admin", newAdminHook),
+		},
 	}
+}
 
-	return c, nil
+// server is the root of the graph: every component Run starts.
+type server struct {
+	dig.In
+
+	Config    *config.Config
+	Logger    *zap.Logger
+	Lifecycle *Lifecycle
+
+	// Start order; they stop in reverse. Health goes NOT_SERVING first so
+	// load balancers drain, and the tracer flushes last.
+	Tracer  Hook `name:"tracer"`
+	Storage Hook `name:"storage"`
+	Metrics Hook `name:"metrics"`
+	GRPC    Hook `name:"grpc"`
+	Gateway Hook `name:"gateway"`
+	Admin   Hook `name:"admin"`
+	Health  Hook `name:"health"`
+}
+
+// Run validates and builds modules, starts every component and blocks
+// until ctx is cancelled or a component fails. It returns the process exit
+// code.
+func Run(ctx context.Context, modules ...Module) int {
+	root := func(s server) {}
+	if err := Validate(modules, root); err != nil {
+		log.Printf("invalid dependency wiring: %v", err)
+		return ExitStartup
+	}
+
+	c, err := Build(modules...)
+	if err != nil {
+		log.Printf("failed to build container: %v", err)
+		return ExitStartup
+	}
+
+	code := ExitStartup
+	err = c.Invoke(func(s server) {
+		defer s.Logger.Sync()
+
+		s.Logger.Info("effective configuration", zap.Object("config", s.Config))
+
+		for _, h := range []Hook{s.Tracer, s.Storage, s.Metrics, s.GRPC, s.Gateway, s.Admin, s.Health} {
+			s.Lifecycle.Append(h)
+		}
+		code = s.Lifecycle.Run(ctx)
+	})
+	if err != nil {
+		log.Printf("failed to invoke container: %v", err)
+		return ExitStartup
+	}
+	return code
 }
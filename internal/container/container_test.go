@@ -1,24 +1,76 @@
 package container
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
 	"grpc-blog/internal/app/blog"
+	"grpc-blog/internal/config"
+	"grpc-blog/internal/infra/health"
+	"grpc-blog/internal/infra/metrics"
+	"grpc-blog/internal/infra/tracing"
+	"grpc-blog/proto/blogpb"
 )
 
+// testConfig listens on ephemeral ports and exports no spans.
+func testConfig() *config.Config {
+	cfg := config.Default()
+	cfg.Server.Addr = "127.0.0.1:0"
+	cfg.Gateway.Addr = "127.0.0.1:0"
+	cfg.Metrics.Addr = "127.0.0.1:0"
+	cfg.Admin.Addr = "127.0.0.1:0"
+	cfg.Admin.Token = "test-token"
+	cfg.Tracing.Exporter = tracing.ExporterNone
+	return cfg
+}
+
+// fakeStore records inserts and can be made unhealthy.
+type fakeStore struct {
+	*blog.MemoryStore
+	inserted []string
+	pingErr  error
+}
+
+func (f *fakeStore) Insert(ctx context.Context, post *blogpb.BlogPost) error {
+	f.inserted = append(f.inserted, post.Title)
+	return f.MemoryStore.Insert(ctx, post)
+}
+
+func (f *fakeStore) Ping(context.Context) error { return f.pingErr }
+
+func fakeStorageModule(store *fakeStore) Module {
+	return Module{
+		Name: "fake-storage",
+		Providers: []Provider{
+			Provide(func() blog.Store { return store }),
+			ProvideNamed("storage", newStorageHook),
+		},
+	}
+}
+
 func TestBuildContainer(t *testing.T) {
-	c, err := Build()
+	c, err := Build(ServerModules(config.Default())...)
 	if err != nil {
 		t.Fatalf("failed to build container: %v", err)
 	}
 
 	err = c.Invoke(func(
+		cfg *config.Config,
 		logger *zap.Logger,
 		service *blog.Service,
+		registry *prometheus.Registry,
+		rpcMetrics *metrics.RPCMetrics,
+		checker *health.Checker,
+		lifecycle *Lifecycle,
+		grpcServer *grpc.Server,
 	) {
-		if logger == nil || service == nil {
+		if grpcServer == nil || lifecycle == nil || checker == nil || cfg == nil || logger == nil || service == nil || registry == nil || rpcMetrics == nil {
 			t.Fatal("dependencies not resolved")
 		}
 	})
@@ -27,3 +79,130 @@ func TestBuildContainer(t *testing.T) {
 		t.Fatalf("failed to invoke container: %v", err)
 	}
 }
+
+func TestAdminServiceOffPublicPort(t *testing.T) {
+	cfg := testConfig()
+	cfg.Backup.Dir = t.TempDir()
+	cfg.Server.Reflection = true
+
+	c, err := Build(ServerModules(cfg)...)
+	if err != nil {
+		t.Fatalf("failed to build container: %v", err)
+	}
+	err = c.Invoke(func(grpcServer *grpc.Server) {
+		if _, ok := grpcServer.GetServiceInfo()[blogpb.AdminService_ServiceDesc.ServiceName]; ok {
+			t.Error("expected AdminService to stay off the public gRPC server")
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to invoke container: %v", err)
+	}
+}
+
+func TestServerModulesValidate(t *testing.T) {
+	if err := Validate(ServerModules(config.Default()), func(server) {}); err != nil {
+		t.Fatalf("server wiring is invalid: %v", err)
+	}
+}
+
+func TestValidateReportsMissingAndUnused(t *testing.T) {
+	type orphan struct{}
+
+	modules := []Module{
+		{Name: "a", Providers: []Provider{
+			Provide(func(*zap.Logger) *blog.Service { return nil }),
+			Provide(func() orphan { return orphan{} }),
+		}},
+	}
+
+	err := Validate(modules, func(*blog.Service) {})
+	if err == nil {
+		t.Fatal("expected wiring errors")
+	}
+	if !strings.Contains(err.Error(), "missing provider for *zap.Logger") {
+		t.Errorf("expected missing logger to be reported, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "unused provider") || !strings.Contains(err.Error(), "orphan") {
+		t.Errorf("expected unused provider to be reported, got %v", err)
+	}
+}
+
+func TestValidateRejectsDuplicateInModule(t *testing.T) {
+	modules := []Module{
+		{Name: "a", Providers: []Provider{
+			Provide(func() *blog.MemoryStore { return nil }),
+			Provide(func() *blog.MemoryStore { return nil }),
+		}},
+	}
+
+	if err := Validate(modules); err == nil || !strings.Contains(err.Error(), "provided twice") {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+}
+
+func TestValidateRejectsPartialOverride(t *testing.T) {
+	type a struct{}
+	type b struct{}
+
+	modules := []Module{
+		{Name: "base", Providers: []Provider{Provide(func() (a, b) { return a{}, b{} })}},
+		{Name: "override", Providers: []Provider{Provide(func() a { return a{} })}},
+	}
+	if err := Validate(modules); err == nil || !strings.Contains(err.Error(), "but not container.b") {
+		t.Fatalf("expected partial override error, got %v", err)
+	}
+
+	// Each override must cover every output on its own.
+	modules[1].Providers = append(modules[1].Providers, Provide(func() b { return b{} }))
+	if err := Validate(modules); err == nil || !strings.Contains(err.Error(), "but not container.b") {
+		t.Fatalf("expected partial override error, got %v", err)
+	}
+	// Overriding every output replaces the provider.
+	modules[1].Providers = []Provider{Provide(func() (b, a, error) { return b{}, a{}, nil })}
+	if err := Validate(modules, func(a, b) {}); err != nil {
+		t.Fatalf("expected a full override to be valid, got %v", err)
+	}
+}
+
+func TestOverrideStorage(t *testing.T) {
+	store := &fakeStore{MemoryStore: blog.NewMemoryStore()}
+	modules := append(ServerModules(config.Default()), fakeStorageModule(store))
+
+	if err := Validate(modules, func(server) {}); err != nil {
+		t.Fatalf("override left invalid wiring: %v", err)
+	}
+
+	c, err := Build(modules...)
+	if err != nil {
+		t.Fatalf("failed to build container: %v", err)
+	}
+
+	err = c.Invoke(func(service *blog.Service) {
+		service.CreatePost(context.Background(), &blogpb.BlogPost{Title: "via fake"})
+	})
+	if err != nil {
+		t.Fatalf("failed to invoke container: %v", err)
+	}
+
+	if len(store.inserted) != 1 || store.inserted[0] != "via fake" {
+		t.Fatalf("expected the fake store to be used, got %v", store.inserted)
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // start everything, then shut down straight away
+
+	if code := Run(ctx, ServerModules(testConfig())...); code != ExitOK {
+		t.Fatalf("expected ExitOK, got %d", code)
+	}
+}
+
+func TestRunStorageUnavailable(t *testing.T) {
+	store := &fakeStore{MemoryStore: blog.NewMemoryStore(), pingErr: errors.New("connection refused")}
+	modules := append(ServerModules(testConfig()), fakeStorageModule(store))
+
+	if code := Run(context.Background(), modules...); code != ExitStartup {
+		t.Fatalf("expected ExitStartup, got %d", code)
+	}
+}
@@ -0,0 +1,167 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Process exit codes returned by Lifecycle.Run and used by cmd/server.
+const (
+	// ExitOK means every component started and stopped cleanly.
+	ExitOK = 0
+	// ExitFailure means a running component failed.
+	ExitFailure = 1
+	// ExitConfig means the configuration was invalid.
+	ExitConfig = 2
+	// ExitStartup means a component failed to start.
+	ExitStartup = 3
+	// ExitShutdown means stopping missed the drain deadline or a component
+	// failed to stop.
+	ExitShutdown = 4
+)
+
+// Hook starts and stops one component. Either function may be nil.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	// OnStop must return promptly once ctx is done, forcing the component
+	// down if draining has not finished by then.
+	OnStop func(ctx context.Context) error
+}
+
+// Lifecycle starts components in the order they were appended and stops
+// them in reverse, so dependencies outlive their dependents.
+type Lifecycle struct {
+	logger *zap.Logger
+	drain  time.Duration
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+
+	failed   chan error
+	failOnce sync.Once
+}
+
+// NewLifecycle returns an empty Lifecycle whose Run gives components drain
+// time to stop once shutdown begins.
+func NewLifecycle(logger *zap.Logger, drain time.Duration) *Lifecycle {
+	return &Lifecycle{
+		logger: logger,
+		drain:  drain,
+		failed: make(chan error, 1),
+	}
+}
+
+// Append registers h after the hooks already added.
+func (l *Lifecycle) Append(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, h)
+}
+
+// Go runs a background loop of the named component, such as a server's
+// Serve method. A non-nil error from run is reported through Fail.
+func (l *Lifecycle) Go(name string, run func() error) {
+	go func() {
+		if err := run(); err != nil {
+			l.Fail(fmt.Errorf("%s: %w", name, err))
+		}
+	}()
+}
+
+// Fail reports that a running component broke and the process should shut
+// down. Only the first failure is kept.
+func (l *Lifecycle) Fail(err error) {
+	l.failOnce.Do(func() { l.failed <- err })
+}
+
+// Start runs every OnStart in order. If one fails, the components already
+// started are stopped within the drain deadline and the error is returned.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	for _, h := range hooks[l.started:] {
+		if h.OnStart != nil {
+			start := time.Now()
+			if err := h.OnStart(ctx); err != nil {
+				err = fmt.Errorf("start %s: %w", h.Name, err)
+
+				stopCtx, cancel := context.WithTimeout(context.Background(), l.drain)
+				defer cancel()
+				return errors.Join(err, l.Stop(stopCtx))
+			}
+			l.logger.Info("component started", zap.String("component", h.Name), zap.Duration("took", time.Since(start)))
+		}
+		l.started++
+	}
+	return nil
+}
+
+// Stop runs OnStop for every started component in reverse order. Each hook
+// is called even when ctx has expired, so it can force its component down;
+// all errors are returned joined.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := l.hooks[:l.started]
+	l.started = 0
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.OnStop == nil {
+			continue
+		}
+
+		start := time.Now()
+		if err := h.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.Name, err))
+			continue
+		}
+		l.logger.Info("component stopped", zap.String("component", h.Name), zap.Duration("took", time.Since(start)))
+	}
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("drain deadline exceeded: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// Run starts every component, waits until ctx is cancelled, typically by a
+// signal, or a component fails, then stops everything within the drain
+// deadline. It returns the process exit code.
+func (l *Lifecycle) Run(ctx context.Context) int {
+	if err := l.Start(ctx); err != nil {
+		l.logger.Error("startup failed", zap.Error(err))
+		return ExitStartup
+	}
+
+	code := ExitOK
+	select {
+	case <-ctx.Done():
+		l.logger.Info("shutting down", zap.Duration("drain", l.drain))
+	case err := <-l.failed:
+		l.logger.Error("component failed, shutting down", zap.Error(err))
+		code = ExitFailure
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), l.drain)
+	defer cancel()
+
+	if err := l.Stop(stopCtx); err != nil {
+		l.logger.Error("shutdown incomplete", zap.Error(err))
+		if code == ExitOK {
+			code = ExitShutdown
+		}
+	}
+	return code
+}
@@ -0,0 +1,118 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// recorder returns a hook appending "start <name>" and "stop <name>" to
+// events.
+func recorder(name string, events *[]string) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			*events = append(*events, "start "+name)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			*events = append(*events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestLifecycleOrder(t *testing.T) {
+	var events []string
+
+	lc := NewLifecycle(zaptest.NewLogger(t), time.Second)
+	lc.Append(recorder("a", &events))
+	lc.Append(recorder("b", &events))
+	lc.Append(recorder("c", &events))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if code := lc.Run(ctx); code != ExitOK {
+		t.Fatalf("expected ExitOK, got %d", code)
+	}
+
+	want := "start a,start b,start c,stop c,stop b,stop a"
+	if got := strings.Join(events, ","); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestLifecycleStartFailureStopsStarted(t *testing.T) {
+	var events []string
+
+	lc := NewLifecycle(zaptest.NewLogger(t), time.Second)
+	lc.Append(recorder("a", &events))
+	lc.Append(Hook{
+		Name:    "broken",
+		OnStart: func(context.Context) error { return errors.New("address in use") },
+		OnStop: func(context.Context) error {
+			t.Fatal("a component that failed to start must not be stopped")
+			return nil
+		},
+	})
+	lc.Append(recorder("c", &events))
+
+	if code := lc.Run(context.Background()); code != ExitStartup {
+		t.Fatalf("expected ExitStartup, got %d", code)
+	}
+
+	if got := strings.Join(events, ","); got != "start a,stop a" {
+		t.Fatalf("unexpected events %s", got)
+	}
+}
+
+func TestLifecycleComponentFailure(t *testing.T) {
+	var events []string
+
+	lc := NewLifecycle(zaptest.NewLogger(t), time.Second)
+	lc.Append(recorder("a", &events))
+	lc.Append(Hook{
+		Name: "server",
+		OnStart: func(context.Context) error {
+			lc.Go("server", func() error { return errors.New("accept failed") })
+			return nil
+		},
+	})
+
+	if code := lc.Run(context.Background()); code != ExitFailure {
+		t.Fatalf("expected ExitFailure, got %d", code)
+	}
+	if got := strings.Join(events, ","); got != "start a,stop a" {
+		t.Fatalf("expected a to be stopped after the failure, got %s", got)
+	}
+}
+
+func TestLifecycleDrainDeadline(t *testing.T) {
+	var forced bool
+
+	lc := NewLifecycle(zaptest.NewLogger(t), 20*time.Millisecond)
+	lc.Append(Hook{
+		Name: "slow",
+		OnStop: func(ctx context.Context) error {
+			<-ctx.Done() // in-flight work never finishes on its own
+			forced = true
+			return ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if code := lc.Run(ctx); code != ExitShutdown {
+		t.Fatalf("expected ExitShutdown, got %d", code)
+	}
+	if !forced || time.Since(start) > time.Second {
+		t.Fatal("expected stop to be forced at the drain deadline")
+	}
+}
@@ -0,0 +1,278 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	"go.uber.org/dig"
+)
+
+// Provider is a constructor registered with the container. When Name is
+// set, the results are registered as named values (dig.Name).
+type Provider struct {
+	Constructor any
+	Name        string
+}
+
+// Provide returns an unnamed Provider for constructor.
+func Provide(constructor any) Provider {
+	return Provider{Constructor: constructor}
+}
+
+// ProvideNamed returns a Provider registering the results of constructor
+// under name.
+func ProvideNamed(name string, constructor any) Provider {
+	return Provider{Constructor: constructor, Name: name}
+}
+
+// Module groups the providers of one subsystem so that cmd/server and
+// tests can mix modules and swap one for another, e.g. a fake storage.
+type Module struct {
+	Name      string
+	Providers []Provider
+}
+
+// Build returns a container holding the providers of modules.
+//
+// A provider in a later module replaces any provider of the same type and
+// name from an earlier module, so overrides are passed last.
+func Build(modules ...Module) (*dig.Container, error) {
+	entries, err := resolve(modules)
+	if err != nil {
+		return nil, err
+	}
+
+	c := dig.New()
+	for _, e := range entries {
+		var opts []dig.ProvideOption
+		if e.provider.Name != "" {
+			opts = append(opts, dig.Name(e.provider.Name))
+		}
+		if err := c.Provide(e.provider.Constructor, opts...); err != nil {
+			return nil, fmt.Errorf("module %s: %w", e.module, err)
+		}
+	}
+	return c, nil
+}
+
+// Validate checks the wiring of modules before anything is constructed.
+//
+// roots are the functions that will be passed to Invoke. Validate reports
+// every dependency needed by the roots that no module provides, and every
+// provider that the roots never use, directly or indirectly.
+func Validate(modules []Module, roots ...any) error {
+	entries, err := resolve(modules)
+	if err != nil {
+		return err
+	}
+
+	providers := make(map[key]int)
+	for i, e := range entries {
+		for _, out := range e.outputs {
+			providers[out] = i
+		}
+	}
+
+	type need struct {
+		dep
+		by string
+	}
+
+	var queue []need
+	for _, root := range roots {
+		ins, err := inputs(reflect.TypeOf(root))
+		if err != nil {
+			return err
+		}
+		for _, d := range ins {
+			queue = append(queue, need{d, funcName(root)})
+		}
+	}
+
+	var errs []error
+	used := make([]bool, len(entries))
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		i, ok := providers[n.key]
+		if !ok {
+			if !n.optional {
+				errs = append(errs, fmt.Errorf("missing provider for %s, required by %s", n.key, n.by))
+			}
+			continue
+		}
+		if used[i] {
+			continue
+		}
+		used[i] = true
+
+		for _, d := range entries[i].inputs {
+			queue = append(queue, need{d, entries[i].String()})
+		}
+	}
+
+	for i, e := range entries {
+		if !used[i] {
+			errs = append(errs, fmt.Errorf("unused provider %s", e))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// key identifies a value in the container.
+type key struct {
+	t    reflect.Type
+	name string
+}
+
+func (k key) String() string {
+	if k.name != "" {
+		return fmt.Sprintf("%v[name=%q]", k.t, k.name)
+	}
+	return k.t.String()
+}
+
+type dep struct {
+	key
+	optional bool
+}
+
+type entry struct {
+	module   string
+	provider Provider
+	outputs  []key
+	inputs   []dep
+}
+
+func (e entry) String() string {
+	outs := make([]string, len(e.outputs))
+	for i, k := range e.outputs {
+		outs[i] = k.String()
+	}
+	return fmt.Sprintf("%s (module %s, provides %s)", funcName(e.provider.Constructor), e.module, strings.Join(outs, ", "))
+}
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	inType    = reflect.TypeOf(dig.In{})
+)
+
+// resolve flattens modules into providers, applying overrides. Providing
+// the same value twice within one module is an error, and so is overriding
+// only some of the values one earlier provider returns: dig builds them
+// together, so the rest would be lost with it.
+func resolve(modules []Module) ([]entry, error) {
+	var entries []entry
+	owner := make(map[key]int)
+
+	for _, m := range modules {
+		seen := make(map[key]bool)
+
+		for _, p := range m.Providers {
+			t := reflect.TypeOf(p.Constructor)
+			if t == nil || t.Kind() != reflect.Func {
+				return nil, fmt.Errorf("module %s: constructor %T is not a function", m.Name, p.Constructor)
+			}
+
+			e := entry{module: m.Name, provider: p}
+			for i := 0; i < t.NumOut(); i++ {
+				if out := t.Out(i); out != errorType {
+					e.outputs = append(e.outputs, key{out, p.Name})
+				}
+			}
+
+			ins, err := inputs(t)
+			if err != nil {
+				return nil, fmt.Errorf("module %s: %w", m.Name, err)
+			}
+			e.inputs = ins
+
+			for _, out := range e.outputs {
+				if seen[out] {
+					return nil, fmt.Errorf("module %s: %s provided twice", m.Name, out)
+				}
+				seen[out] = true
+
+				prev, ok := owner[out]
+				if !ok {
+					continue
+				}
+				for _, other := range entries[prev].outputs {
+					if !slices.Contains(e.outputs, other) {
+						return nil, fmt.Errorf("module %s: %s overrides %s but not %s from %s",
+							m.Name, funcName(p.Constructor), out, other, entries[prev])
+					}
+				}
+			}
+			for _, out := range e.outputs {
+				if prev, ok := owner[out]; ok {
+					// Overridden: drop the earlier provider entirely.
+					entries[prev].outputs = nil
+				}
+				owner[out] = len(entries)
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	kept := entries[:0]
+	for _, e := range entries {
+		if len(e.outputs) > 0 {
+			kept = append(kept, e)
+		}
+	}
+	return kept, nil
+}
+
+// inputs lists the parameters of function type t, expanding dig.In
+// structs into their fields.
+func inputs(t reflect.Type) ([]dep, error) {
+	if t == nil || t.Kind() != reflect.Func {
+		return nil, fmt.Errorf("%v is not a function", t)
+	}
+
+	var deps []dep
+	for i := 0; i < t.NumIn(); i++ {
+		in := t.In(i)
+		if !isParamObject(in) {
+			deps = append(deps, dep{key: key{t: in}})
+			continue
+		}
+
+		for j := 0; j < in.NumField(); j++ {
+			f := in.Field(j)
+			if f.Anonymous && f.Type == inType {
+				continue
+			}
+			deps = append(deps, dep{
+				key:      key{t: f.Type, name: f.Tag.Get("name")},
+				optional: f.Tag.Get("optional") == "true",
+			})
+		}
+	}
+	return deps, nil
+}
+
+func isParamObject(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type == inType {
+			return true
+		}
+	}
+	return false
+}
+
+func funcName(fn any) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return fmt.Sprintf("%T", fn)
+}
@@ -0,0 +1,315 @@
+// Package dynamic calls arbitrary gRPC methods using descriptors obtained
+// through server reflection, with JSON input and output.
+//
+// It lets tools exercise new RPCs without regenerating client stubs.
+package dynamic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// reflectionPrefix marks the reflection services themselves, which are
+// excluded from ListServices output.
+const reflectionPrefix = "grpc.reflection."
+
+// Client resolves descriptors lazily through the reflection service and
+// caches them for the lifetime of the Client. It is not safe for
+// concurrent use.
+type Client struct {
+	conn  grpc.ClientConnInterface
+	refl  reflectionpb.ServerReflectionClient
+	files *protoregistry.Files
+}
+
+// NewClient returns a Client issuing reflection and RPC calls on conn.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{
+		conn:  conn,
+		refl:  reflectionpb.NewServerReflectionClient(conn),
+		files: new(protoregistry.Files),
+	}
+}
+
+// ListServices returns the fully-qualified names of every service exposed
+// by the server, sorted, excluding the reflection service itself.
+func (c *Client) ListServices(ctx context.Context) ([]string, error) {
+	resp, err := c.request(ctx, &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		if !strings.HasPrefix(svc.Name, reflectionPrefix) {
+			names = append(names, svc.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DescribeService returns the descriptor of the named service.
+func (c *Client) DescribeService(ctx context.Context, name string) (protoreflect.ServiceDescriptor, error) {
+	desc, err := c.resolveSymbol(ctx, protoreflect.FullName(name))
+	if err != nil {
+		return nil, err
+	}
+
+	svc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", name)
+	}
+	return svc, nil
+}
+
+// Invoke calls method with the JSON-encoded request and returns each
+// response message encoded as JSON through emit.
+//
+// method is "package.Service/Method" or "package.Service.Method". Unary and
+// server-streaming methods are supported.
+func (c *Client) Invoke(ctx context.Context, method string, input []byte, emit func([]byte) error) error {
+	md, err := c.resolveMethod(ctx, method)
+	if err != nil {
+		return err
+	}
+	if md.IsStreamingClient() {
+		return fmt.Errorf("%s: client streaming is not supported", md.FullName())
+	}
+
+	req := dynamicpb.NewMessage(md.Input())
+	if len(strings.TrimSpace(string(input))) > 0 {
+		if err := (protojson.UnmarshalOptions{Resolver: c.types()}).Unmarshal(input, req); err != nil {
+			return fmt.Errorf("parse request: %w", err)
+		}
+	}
+
+	path := fmt.Sprintf("/%s/%s", md.Parent().FullName(), md.Name())
+
+	if !md.IsStreamingServer() {
+		resp := dynamicpb.NewMessage(md.Output())
+		if err := c.conn.Invoke(ctx, path, req, resp); err != nil {
+			return err
+		}
+		return c.emit(resp, emit)
+	}
+
+	stream, err := c.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, path)
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	for {
+		resp := dynamicpb.NewMessage(md.Output())
+		if err := stream.RecvMsg(resp); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := c.emit(resp, emit); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) emit(m proto.Message, emit func([]byte) error) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Resolver: c.types()}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return emit(data)
+}
+
+// types resolves message types, including google.protobuf.Any payloads,
+// from the descriptors fetched so far.
+func (c *Client) types() *dynamicpb.Types {
+	return dynamicpb.NewTypes(c.files)
+}
+
+func (c *Client) resolveMethod(ctx context.Context, method string) (protoreflect.MethodDescriptor, error) {
+	method = strings.TrimPrefix(method, "/")
+
+	var service, name string
+	if i := strings.LastIndex(method, "/"); i >= 0 {
+		service, name = method[:i], method[i+1:]
+	} else if i := strings.LastIndex(method, "."); i >= 0 {
+		service, name = method[:i], method[i+1:]
+	} else {
+		return nil, fmt.Errorf("invalid method %q: want package.Service/Method", method)
+	}
+
+	svc, err := c.DescribeService(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	md := svc.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, fmt.Errorf("service %s has no method %s", service, name)
+	}
+	return md, nil
+}
+
+func (c *Client) resolveSymbol(ctx context.Context, name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if desc, err := c.files.FindDescriptorByName(name); err == nil {
+		return desc, nil
+	}
+
+	resp, err := c.request(ctx, &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: string(name),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.register(ctx, resp.GetFileDescriptorResponse().GetFileDescriptorProto()); err != nil {
+		return nil, err
+	}
+	return c.files.FindDescriptorByName(name)
+}
+
+// register decodes serialized file descriptors and adds them, and any
+// missing dependencies, to the local registry.
+func (c *Client) register(ctx context.Context, raw [][]byte) error {
+	pending := make(map[string]*descriptorpb.FileDescriptorProto, len(raw))
+	for _, b := range raw {
+		fdp := new(descriptorpb.FileDescriptorProto)
+		if err := proto.Unmarshal(b, fdp); err != nil {
+			return fmt.Errorf("decode descriptor: %w", err)
+		}
+		pending[fdp.GetName()] = fdp
+	}
+
+	var add func(name string) error
+	add = func(name string) error {
+		if _, err := c.files.FindFileByPath(name); err == nil {
+			return nil
+		}
+
+		fdp, ok := pending[name]
+		if !ok {
+			fetched, err := c.fetchFile(ctx, name)
+			if err != nil {
+				return err
+			}
+			if fetched == nil {
+				// Unknown to the server; fall back to the descriptors
+				// linked into this binary, e.g. well-known types.
+				fd, err := protoregistry.GlobalFiles.FindFileByPath(name)
+				if err != nil {
+					return fmt.Errorf("resolve %s: %w", name, err)
+				}
+				return c.files.RegisterFile(fd)
+			}
+			for k, v := range fetched {
+				pending[k] = v
+			}
+			fdp = pending[name]
+		}
+
+		for _, dep := range fdp.GetDependency() {
+			if err := add(dep); err != nil {
+				return err
+			}
+		}
+
+		fd, err := protodesc.NewFile(fdp, c.files)
+		if err != nil {
+			return fmt.Errorf("build descriptor %s: %w", name, err)
+		}
+		return c.files.RegisterFile(fd)
+	}
+
+	for name := range pending {
+		if err := add(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchFile asks the server for a file by name. It returns nil, nil when
+// the server does not know the file.
+func (c *Client) fetchFile(ctx context.Context, name string) (map[string]*descriptorpb.FileDescriptorProto, error) {
+	resp, err := c.request(ctx, &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+	})
+	var rerr *reflectionError
+	if errors.As(err, &rerr) && rerr.code == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fdp := new(descriptorpb.FileDescriptorProto)
+		if err := proto.Unmarshal(b, fdp); err != nil {
+			return nil, fmt.Errorf("decode descriptor: %w", err)
+		}
+		files[fdp.GetName()] = fdp
+	}
+	if _, ok := files[name]; !ok {
+		return nil, nil
+	}
+	return files, nil
+}
+
+// request performs a single reflection round trip.
+func (c *Client) request(ctx context.Context, req *reflectionpb.ServerReflectionRequest) (*reflectionpb.ServerReflectionResponse, error) {
+	stream, err := c.refl.ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, &reflectionError{code: codes.Code(e.GetErrorCode()), msg: e.GetErrorMessage()}
+	}
+	return resp, nil
+}
+
+// reflectionError is an error reported in-band by the reflection service.
+type reflectionError struct {
+	code codes.Code
+	msg  string
+}
+
+func (e *reflectionError) Error() string {
+	return fmt.Sprintf("reflection: %s: %s", e.code, e.msg)
+}
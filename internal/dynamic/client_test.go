@@ -0,0 +1,119 @@
+package dynamic
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+
+	"grpc-blog/internal/app/blog"
+	grpctransport "grpc-blog/internal/transport/grpc"
+	"grpc-blog/proto/blogpb"
+)
+
+func setupReflectionServer(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer()
+	blogpb.RegisterBlogServiceServer(server, grpctransport.NewBlogGRPCServer(blog.NewService(zaptest.NewLogger(t), blog.NewMemoryStore())))
+	reflection.Register(server)
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestListServices(t *testing.T) {
+	client := NewClient(setupReflectionServer(t))
+
+	services, err := client.ListServices(context.Background())
+	if err != nil {
+		t.Fatalf("ListServices failed: %v", err)
+	}
+
+	if len(services) != 1 || services[0] != "blog.BlogService" {
+		t.Fatalf("unexpected services %v", services)
+	}
+}
+
+func TestDescribeService(t *testing.T) {
+	client := NewClient(setupReflectionServer(t))
+
+	svc, err := client.DescribeService(context.Background(), "blog.BlogService")
+	if err != nil {
+		t.Fatalf("DescribeService failed: %v", err)
+	}
+
+	if svc.Methods().ByName("CreatePost") == nil {
+		t.Fatal("expected CreatePost method")
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	client := NewClient(setupReflectionServer(t))
+	ctx := context.Background()
+
+	var created string
+	err := client.Invoke(ctx, "blog.BlogService/CreatePost",
+		[]byte(`{"title":"dynamic","publicationDate":"2024-01-02T03:04:05Z","tags":["a"]}`),
+		func(out []byte) error {
+			created = string(out)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if !strings.Contains(created, `"dynamic"`) || !strings.Contains(created, "2024-01-02T03:04:05Z") {
+		t.Fatalf("unexpected response %s", created)
+	}
+
+	var all string
+	err = client.Invoke(ctx, "blog.BlogService.ReadAll", nil, func(out []byte) error {
+		all = string(out)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if !strings.Contains(all, `"dynamic"`) {
+		t.Fatalf("expected created post in ReadAll, got %s", all)
+	}
+}
+
+func TestInvokeErrors(t *testing.T) {
+	client := NewClient(setupReflectionServer(t))
+	ctx := context.Background()
+	noop := func([]byte) error { return nil }
+
+	if err := client.Invoke(ctx, "blog.BlogService/Missing", nil, noop); err == nil {
+		t.Fatal("expected error for unknown method")
+	}
+	if err := client.Invoke(ctx, "blog.Nope/ReadAll", nil, noop); err == nil {
+		t.Fatal("expected error for unknown service")
+	}
+	if err := client.Invoke(ctx, "blog.BlogService/ReadPost", []byte(`{"unknown":1}`), noop); err == nil {
+		t.Fatal("expected error for invalid JSON input")
+	}
+}
@@ -0,0 +1,143 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Check reports whether a dependency is usable. A nil error means healthy.
+type Check func(ctx context.Context) error
+
+// Config controls how often dependency checks run.
+type Config struct {
+	// Interval between two evaluations of every check.
+	Interval time.Duration `yaml:"interval"`
+	// Timeout bounds a single check.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// DefaultConfig returns the default check schedule.
+func DefaultConfig() Config {
+	return Config{
+		Interval: 10 * time.Second,
+		Timeout:  2 * time.Second,
+	}
+}
+
+// Checker aggregates dependency checks into the standard grpc.health.v1
+// serving status.
+//
+// Each gRPC service name is SERVING only when all of its checks pass; the
+// overall status (empty service name) is SERVING only when every service
+// is. Everything reports NOT_SERVING until the first evaluation and again
+// after Shutdown.
+type Checker struct {
+	cfg    Config
+	logger *zap.Logger
+	server *grpchealth.Server
+
+	mu     sync.Mutex
+	checks map[string]map[string]Check
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewChecker constructs a Checker whose services start as NOT_SERVING.
+func NewChecker(cfg Config, logger *zap.Logger) *Checker {
+	server := grpchealth.NewServer()
+	server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return &Checker{
+		cfg:    cfg,
+		logger: logger,
+		server: server,
+		checks: make(map[string]map[string]Check),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Server returns the grpc.health.v1 implementation to register on a gRPC server.
+func (c *Checker) Server() healthpb.HealthServer {
+	return c.server
+}
+
+// Register adds a named check contributing to the status of service.
+func (c *Checker) Register(service, name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.checks[service] == nil {
+		c.checks[service] = make(map[string]Check)
+		c.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	c.checks[service][name] = check
+}
+
+// Start evaluates every check immediately, then every Interval until Shutdown.
+func (c *Checker) Start() {
+	c.Evaluate(context.Background())
+
+	go func() {
+		ticker := time.NewTicker(c.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.Evaluate(context.Background())
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Evaluate runs every check once and publishes the resulting statuses.
+func (c *Checker) Evaluate(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	overall := healthpb.HealthCheckResponse_SERVING
+	for service, checks := range c.checks {
+		status := healthpb.HealthCheckResponse_SERVING
+
+		for name, check := range checks {
+			checkCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+			err := check(checkCtx)
+			cancel()
+
+			if err != nil {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+				c.logger.Warn("health check failed",
+					zap.String("service", service),
+					zap.String("check", name),
+					zap.Error(err),
+				)
+			}
+		}
+
+		if status != healthpb.HealthCheckResponse_SERVING {
+			overall = status
+		}
+		c.server.SetServingStatus(service, status)
+	}
+
+	c.server.SetServingStatus("", overall)
+}
+
+// Shutdown marks every service NOT_SERVING and stops evaluating checks.
+//
+// Call it before GracefulStop so load balancers stop routing new requests
+// while in-flight ones drain. Later status updates are ignored.
+func (c *Checker) Shutdown() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		c.server.Shutdown()
+	})
+}
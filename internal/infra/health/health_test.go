@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func status(t *testing.T, c *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	resp, err := c.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("health check failed: %v", err)
+	}
+	return resp.Status
+}
+
+func TestCheckerStartsNotServing(t *testing.T) {
+	c := NewChecker(DefaultConfig(), zaptest.NewLogger(t))
+	c.Register("blog.BlogService", "storage", func(context.Context) error { return nil })
+
+	if got := status(t, c, ""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected NOT_SERVING before first evaluation, got %v", got)
+	}
+	if got := status(t, c, "blog.BlogService"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected NOT_SERVING before first evaluation, got %v", got)
+	}
+}
+
+func TestCheckerAggregatesPerService(t *testing.T) {
+	c := NewChecker(DefaultConfig(), zaptest.NewLogger(t))
+
+	healthy := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errors.New("down") }
+
+	c.Register("svc.A", "storage", healthy)
+	c.Register("svc.B", "storage", healthy)
+	c.Register("svc.B", "cache", failing)
+
+	c.Evaluate(context.Background())
+
+	if got := status(t, c, "svc.A"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("expected svc.A SERVING, got %v", got)
+	}
+	if got := status(t, c, "svc.B"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected svc.B NOT_SERVING, got %v", got)
+	}
+	if got := status(t, c, ""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected overall NOT_SERVING, got %v", got)
+	}
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	c := NewChecker(Config{Interval: time.Hour, Timeout: 10 * time.Millisecond}, zaptest.NewLogger(t))
+	c.Register("svc", "slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	c.Evaluate(context.Background())
+
+	if got := status(t, c, "svc"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected timed-out check to fail, got %v", got)
+	}
+}
+
+func TestCheckerShutdown(t *testing.T) {
+	c := NewChecker(Config{Interval: time.Hour, Timeout: time.Second}, zaptest.NewLogger(t))
+	c.Register("svc", "storage", func(context.Context) error { return nil })
+
+	c.Start()
+	if got := status(t, c, ""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING after start, got %v", got)
+	}
+
+	c.Shutdown()
+	c.Evaluate(context.Background())
+
+	if got := status(t, c, "svc"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected NOT_SERVING after shutdown, got %v", got)
+	}
+}
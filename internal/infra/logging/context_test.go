@@ -0,0 +1,22 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestFromContext(t *testing.T) {
+	fallback := zap.NewNop()
+	scoped := zap.NewNop().With(zap.String("request_id", "r1"))
+
+	if FromContext(context.Background(), fallback) != fallback {
+		t.Fatal("expected fallback logger for empty context")
+	}
+
+	ctx := WithContext(context.Background(), scoped)
+	if FromContext(ctx, fallback) != scoped {
+		t.Fatal("expected request-scoped logger")
+	}
+}
@@ -1,9 +1,111 @@
 package logging
 
-import "go.uber.org/zap"
+import (
+	"fmt"
+	"os"
+	"time"
 
-func NewLogger() (*zap.Logger, error) {
-	cfg := zap.NewProductionConfig()
-	cfg.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-	return cfg.Build()
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Supported log encodings.
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+)
+
+// Config controls the application logger.
+type Config struct {
+	// Level is the initial minimum level: debug, info, warn or error.
+	// It can be changed at runtime through the AtomicLevel handler.
+	Level string `yaml:"level"`
+	// Encoding is one of the Encoding* constants.
+	Encoding string         `yaml:"encoding"`
+	Sampling SamplingConfig `yaml:"sampling"`
+	File     FileConfig     `yaml:"file"`
+	Payload  PayloadConfig  `yaml:"payload"`
+}
+
+// SamplingConfig limits repeated log lines per second. Within each second
+// the first Initial entries with the same level and message are logged,
+// then every Thereafter-th one. Sampling is disabled when Initial is zero.
+type SamplingConfig struct {
+	Initial    int `yaml:"initial"`
+	Thereafter int `yaml:"thereafter"`
+}
+
+// FileConfig writes logs to a rotated file instead of stderr when Path is set.
+type FileConfig struct {
+	Path       string `yaml:"path"`
+	MaxSizeMB  int    `yaml:"max_size_mb"`
+	MaxBackups int    `yaml:"max_backups"`
+	MaxAgeDays int    `yaml:"max_age_days"`
+	Compress   bool   `yaml:"compress"`
+}
+
+// PayloadConfig controls debug-level logging of RPC request and response
+// messages by the transport logging interceptors.
+type PayloadConfig struct {
+	// Enabled turns payload logging on; entries are still only written when
+	// the logger is at debug level.
+	Enabled bool `yaml:"enabled"`
+	// SampleRate is the fraction of messages logged, between 0 and 1.
+	SampleRate float64 `yaml:"sample_rate"`
+	// Redact lists protobuf field names whose values are masked, at any depth.
+	Redact []string `yaml:"redact"`
+}
+
+// DefaultConfig returns the production logger settings.
+func DefaultConfig() Config {
+	return Config{
+		Level:    "info",
+		Encoding: EncodingJSON,
+		Sampling: SamplingConfig{Initial: 100, Thereafter: 100},
+		File:     FileConfig{MaxSizeMB: 100},
+		Payload:  PayloadConfig{SampleRate: 1},
+	}
+}
+
+// NewLevel returns the shared, runtime-adjustable level initialised from cfg.
+//
+// The returned AtomicLevel is also an http.Handler: GET reports the current
+// level and PUT {"level":"debug"} changes it.
+func NewLevel(cfg Config) (zap.AtomicLevel, error) {
+	return zap.ParseAtomicLevel(cfg.Level)
+}
+
+func NewLogger(cfg Config, level zap.AtomicLevel) (*zap.Logger, error) {
+	var encoder zapcore.Encoder
+	switch cfg.Encoding {
+	case EncodingJSON, "":
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	case EncodingConsole:
+		encoder = zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	default:
+		return nil, fmt.Errorf("unknown log encoding %q", cfg.Encoding)
+	}
+
+	var sink zapcore.WriteSyncer = zapcore.Lock(os.Stderr)
+	if cfg.File.Path != "" {
+		sink = zapcore.AddSync(&lumberjack.Logger{
+			Filename:   cfg.File.Path,
+			MaxSize:    cfg.File.MaxSizeMB,
+			MaxBackups: cfg.File.MaxBackups,
+			MaxAge:     cfg.File.MaxAgeDays,
+			Compress:   cfg.File.Compress,
+		})
+	}
+
+	core := zapcore.NewCore(encoder, sink, level)
+	if cfg.Sampling.Initial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, cfg.Sampling.Initial, cfg.Sampling.Thereafter)
+	}
+
+	return zap.New(core,
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+	), nil
 }
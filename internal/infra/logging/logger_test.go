@@ -1,13 +1,94 @@
 package logging
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
 
-func TestNewLogger(t *testing.T) {
-	logger, err := NewLogger()
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newLogger(t *testing.T, cfg Config) (*zap.Logger, zap.AtomicLevel) {
+	t.Helper()
+
+	level, err := NewLevel(cfg)
+	if err != nil {
+		t.Fatalf("failed to parse level: %v", err)
+	}
+
+	logger, err := NewLogger(cfg, level)
 	if err != nil {
 		t.Fatalf("failed to create logger: %v", err)
 	}
+	return logger, level
+}
+
+func TestNewLogger(t *testing.T) {
+	logger, _ := newLogger(t, DefaultConfig())
 	if logger == nil {
 		t.Fatal("expected non-nil logger")
 	}
 }
+
+func TestNewLogger_Level(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Level = "warn"
+
+	logger, _ := newLogger(t, cfg)
+	if logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("debug should be disabled at warn level")
+	}
+}
+
+func TestNewLevel_Invalid(t *testing.T) {
+	if _, err := NewLevel(Config{Level: "loud"}); err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+}
+
+func TestNewLogger_InvalidEncoding(t *testing.T) {
+	if _, err := NewLogger(Config{Encoding: "xml"}, zap.NewAtomicLevel()); err == nil {
+		t.Fatal("expected error for invalid encoding")
+	}
+}
+
+func TestNewLogger_File(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Encoding = EncodingConsole
+	cfg.File.Path = filepath.Join(t.TempDir(), "server.log")
+
+	logger, _ := newLogger(t, cfg)
+	logger.Info("written to file")
+	logger.Sync()
+
+	data, err := os.ReadFile(cfg.File.Path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "written to file") {
+		t.Fatalf("expected log line in file, got %q", data)
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	logger, level := newLogger(t, DefaultConfig())
+
+	if logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("debug should start disabled")
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/log/level", strings.NewReader(`{"level":"debug"}`))
+	rec := httptest.NewRecorder()
+	level.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body)
+	}
+	if !logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("debug should be enabled after level change")
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"grpc-blog/internal/app/blog"
+)
+
+var (
+	postsDesc = prometheus.NewDesc(
+		"blog_posts",
+		"Number of posts currently stored.",
+		nil, nil,
+	)
+	postsPerTagDesc = prometheus.NewDesc(
+		"blog_posts_by_tag",
+		"Number of stored posts carrying each tag.",
+		[]string{"tag"}, nil,
+	)
+	storeSizeDesc = prometheus.NewDesc(
+		"blog_store_size_bytes",
+		"Encoded size of all stored posts in bytes.",
+		nil, nil,
+	)
+)
+
+// BlogCollector exposes blog.Service statistics as gauges.
+//
+// Values are computed on every scrape so they never drift from the store.
+type BlogCollector struct {
+	service *blog.Service
+}
+
+// NewBlogCollector returns a collector reading from service.
+func NewBlogCollector(service *blog.Service) *BlogCollector {
+	return &BlogCollector{service: service}
+}
+
+// Describe implements prometheus.Collector.
+func (c *BlogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- postsDesc
+	ch <- postsPerTagDesc
+	ch <- storeSizeDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *BlogCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.service.Stats(context.Background())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(postsDesc, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(postsDesc, prometheus.GaugeValue, float64(stats.TotalPosts))
+	for tag, n := range stats.PostsPerTag {
+		ch <- prometheus.MustNewConstMetric(postsPerTagDesc, prometheus.GaugeValue, float64(n), tag)
+	}
+	ch <- prometheus.MustNewConstMetric(storeSizeDesc, prometheus.GaugeValue, float64(stats.StoreSizeBytes))
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"grpc-blog/internal/app/blog"
+)
+
+// NewRegistry builds the Prometheus registry exposed by the server.
+//
+// The registry carries Go runtime and process collectors as well as the
+// blog domain gauges backed by service.
+func NewRegistry(service *blog.Service) (*prometheus.Registry, error) {
+	reg := prometheus.NewRegistry()
+
+	for _, c := range []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		NewBlogCollector(service),
+	} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return reg, nil
+}
+
+// Handler returns the HTTP handler serving reg in the Prometheus exposition format.
+func Handler(reg *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/codes"
+
+	"grpc-blog/internal/app/blog"
+	"grpc-blog/proto/blogpb"
+)
+
+func TestNewRegistry(t *testing.T) {
+	service := blog.NewService(zaptest.NewLogger(t), blog.NewMemoryStore())
+	service.CreatePost(context.Background(), &blogpb.BlogPost{Title: "a", Tags: []string{"go"}})
+
+	reg, err := NewRegistry(service)
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	expected := `
+# HELP blog_posts Number of posts currently stored.
+# TYPE blog_posts gauge
+blog_posts 1
+# HELP blog_posts_by_tag Number of stored posts carrying each tag.
+# TYPE blog_posts_by_tag gauge
+blog_posts_by_tag{tag="go"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "blog_posts", "blog_posts_by_tag"); err != nil {
+		t.Fatalf("unexpected domain metrics: %v", err)
+	}
+}
+
+func TestRPCMetrics(t *testing.T) {
+	reg, err := NewRegistry(blog.NewService(zaptest.NewLogger(t), blog.NewMemoryStore()))
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	m, err := NewRPCMetrics(reg)
+	if err != nil {
+		t.Fatalf("failed to create rpc metrics: %v", err)
+	}
+
+	m.Observe("/blog.BlogService/ReadPost", codes.OK, 10*time.Millisecond)
+	m.Observe("/blog.BlogService/ReadPost", codes.OK, 20*time.Millisecond)
+
+	if got := testutil.ToFloat64(m.handled.WithLabelValues("/blog.BlogService/ReadPost", "OK")); got != 2 {
+		t.Fatalf("expected 2 handled requests, got %v", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	reg, err := NewRegistry(blog.NewService(zaptest.NewLogger(t), blog.NewMemoryStore()))
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", rec.Code)
+	}
+
+	if !strings.Contains(rec.Body.String(), "blog_store_size_bytes") {
+		t.Fatal("expected domain gauges in exposition")
+	}
+}
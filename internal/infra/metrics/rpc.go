@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+)
+
+// RPCMetrics records per-method gRPC request counts and latencies.
+type RPCMetrics struct {
+	handled *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+	panics  *prometheus.CounterVec
+}
+
+// NewRPCMetrics creates the RPC collectors and registers them with reg.
+func NewRPCMetrics(reg *prometheus.Registry) (*RPCMetrics, error) {
+	m := &RPCMetrics{
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, by method and status code.",
+		}, []string{"method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Latency of RPCs handled by the server, by method and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_panics_total",
+			Help: "Total number of panics recovered in RPC handlers, by method.",
+		}, []string{"method"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.handled, m.latency, m.panics} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Observe records a completed RPC.
+func (m *RPCMetrics) Observe(method string, code codes.Code, latency time.Duration) {
+	m.handled.WithLabelValues(method, code.String()).Inc()
+	m.latency.WithLabelValues(method, code.String()).Observe(latency.Seconds())
+}
+
+// ObservePanic records a panic recovered while handling method.
+func (m *RPCMetrics) ObservePanic(method string) {
+	m.panics.WithLabelValues(method).Inc()
+}
@@ -2,22 +2,130 @@ package tracing
 
 import (
 	"context"
+	"fmt"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
 )
 
-func InitTracer() (func(context.Context) error, error) {
-	exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+// Supported span exporters.
+const (
+	ExporterStdout   = "stdout"
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterNone     = "none"
+)
+
+// Config controls how spans are sampled, described and exported.
+type Config struct {
+	// Exporter is one of the Exporter* constants.
+	Exporter string `yaml:"exporter"`
+	// Endpoint is the host:port of the OTLP collector. When empty the
+	// exporter falls back to the standard OTEL_EXPORTER_OTLP_* variables.
+	Endpoint string `yaml:"endpoint"`
+	// Insecure disables TLS towards the OTLP collector.
+	Insecure bool `yaml:"insecure"`
+	// Headers are sent with every OTLP export, typically for authentication.
+	Headers map[string]string `yaml:"headers"`
+	// ServiceName and ServiceVersion populate the service.* resource attributes.
+	ServiceName    string `yaml:"service_name"`
+	ServiceVersion string `yaml:"service_version"`
+	// SampleRatio is the fraction of root traces sampled; child spans follow
+	// their parent's decision.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// DefaultConfig returns a Config that samples everything and writes spans to stdout.
+func DefaultConfig(serviceName string) Config {
+	return Config{
+		Exporter:    ExporterStdout,
+		ServiceName: serviceName,
+		SampleRatio: 1,
+	}
+}
+
+// InitTracer installs a global TracerProvider built from cfg.
+//
+// The returned function flushes pending spans and releases the exporter.
+func InitTracer(cfg Config) (func(context.Context) error, error) {
+	ctx := context.Background()
+
+	exporter, err := newExporter(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+		),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
 
 	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
 	return tp.Shutdown, nil
 }
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterStdout:
+		return stdouttrace.New()
+
+	case ExporterOTLPGRPC:
+		var opts []otlptracegrpc.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+
+	case ExporterOTLPHTTP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+
+	case ExporterNone:
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+}
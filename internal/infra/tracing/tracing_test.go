@@ -2,11 +2,88 @@ package tracing
 
 import (
 	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+
+	"go.opentelemetry.io/otel"
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 )
 
+// collectorStub is an in-process OTLP trace collector that records every
+// exported resource span.
+type collectorStub struct {
+	collectortracepb.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	spans []*tracepb.ResourceSpans
+}
+
+func (c *collectorStub) Export(
+	ctx context.Context,
+	req *collectortracepb.ExportTraceServiceRequest,
+) (*collectortracepb.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.spans = append(c.spans, req.ResourceSpans...)
+	return &collectortracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (c *collectorStub) received() []*tracepb.ResourceSpans {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.spans
+}
+
+func startGRPCCollector(t *testing.T) (*collectorStub, string) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	stub := &collectorStub{}
+	server := grpc.NewServer()
+	collectortracepb.RegisterTraceServiceServer(server, stub)
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return stub, lis.Addr().String()
+}
+
+func emitSpan(t *testing.T, shutdown func(context.Context) error) {
+	t.Helper()
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test-span")
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+}
+
+func serviceName(rs *tracepb.ResourceSpans) string {
+	for _, attr := range rs.GetResource().GetAttributes() {
+		if attr.Key == "service.name" {
+			return attr.GetValue().GetStringValue()
+		}
+	}
+	return ""
+}
+
 func TestInitTracer(t *testing.T) {
-	shutdown, err := InitTracer()
+	shutdown, err := InitTracer(DefaultConfig("test"))
 	if err != nil {
 		t.Fatalf("failed to init tracer: %v", err)
 	}
@@ -19,3 +96,108 @@ func TestInitTracer(t *testing.T) {
 		t.Fatalf("shutdown failed: %v", err)
 	}
 }
+
+func TestInitTracer_UnknownExporter(t *testing.T) {
+	_, err := InitTracer(Config{Exporter: "zipkin"})
+	if err == nil {
+		t.Fatal("expected error for unknown exporter")
+	}
+}
+
+func TestInitTracer_None(t *testing.T) {
+	shutdown, err := InitTracer(Config{Exporter: ExporterNone, SampleRatio: 1})
+	if err != nil {
+		t.Fatalf("failed to init tracer: %v", err)
+	}
+
+	emitSpan(t, shutdown)
+}
+
+func TestInitTracer_OTLPGRPC(t *testing.T) {
+	stub, addr := startGRPCCollector(t)
+
+	shutdown, err := InitTracer(Config{
+		Exporter:       ExporterOTLPGRPC,
+		Endpoint:       addr,
+		Insecure:       true,
+		ServiceName:    "blog-test",
+		ServiceVersion: "1.2.3",
+		SampleRatio:    1,
+	})
+	if err != nil {
+		t.Fatalf("failed to init tracer: %v", err)
+	}
+
+	emitSpan(t, shutdown)
+
+	spans := stub.received()
+	if len(spans) == 0 {
+		t.Fatal("expected collector to receive spans")
+	}
+
+	if got := serviceName(spans[0]); got != "blog-test" {
+		t.Fatalf("expected service.name blog-test, got %q", got)
+	}
+}
+
+func TestInitTracer_OTLPHTTP(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		req collectortracepb.ExportTraceServiceRequest
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		proto.Unmarshal(body, &req)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer server.Close()
+
+	shutdown, err := InitTracer(Config{
+		Exporter:    ExporterOTLPHTTP,
+		Endpoint:    strings.TrimPrefix(server.URL, "http://"),
+		Insecure:    true,
+		ServiceName: "blog-http",
+		SampleRatio: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to init tracer: %v", err)
+	}
+
+	emitSpan(t, shutdown)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(req.ResourceSpans) == 0 {
+		t.Fatal("expected collector to receive spans")
+	}
+
+	if got := serviceName(req.ResourceSpans[0]); got != "blog-http" {
+		t.Fatalf("expected service.name blog-http, got %q", got)
+	}
+}
+
+func TestInitTracer_SampleRatioZero(t *testing.T) {
+	stub, addr := startGRPCCollector(t)
+
+	shutdown, err := InitTracer(Config{
+		Exporter:    ExporterOTLPGRPC,
+		Endpoint:    addr,
+		Insecure:    true,
+		SampleRatio: 0,
+	})
+	if err != nil {
+		t.Fatalf("failed to init tracer: %v", err)
+	}
+
+	emitSpan(t, shutdown)
+
+	if len(stub.received()) != 0 {
+		t.Fatal("expected no spans to be exported")
+	}
+}
@@ -0,0 +1,99 @@
+package grpctransport
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"grpc-blog/internal/app/backup"
+	"grpc-blog/internal/app/blog"
+	"grpc-blog/proto/blogpb"
+)
+
+// AdminGRPCServer serves AdminService over a backup.Manager. Unlike
+// BlogService it reports errors as status codes.
+type AdminGRPCServer struct {
+	blogpb.UnimplementedAdminServiceServer
+	backups *backup.Manager
+}
+
+func NewAdminGRPCServer(backups *backup.Manager) *AdminGRPCServer {
+	return &AdminGRPCServer{backups: backups}
+}
+
+func (s *AdminGRPCServer) CreateBackup(
+	ctx context.Context,
+	req *blogpb.CreateBackupRequest,
+) (*blogpb.Backup, error) {
+
+	info, err := s.backups.Create(ctx)
+	if err != nil {
+		return nil, adminError(err)
+	}
+	return backupToProto(info), nil
+}
+
+func (s *AdminGRPCServer) ListBackups(
+	ctx context.Context,
+	req *blogpb.ListBackupsRequest,
+) (*blogpb.ListBackupsResponse, error) {
+
+	infos, err := s.backups.List(ctx)
+	if err != nil {
+		return nil, adminError(err)
+	}
+
+	resp := &blogpb.ListBackupsResponse{}
+	for _, info := range infos {
+		resp.Backups = append(resp.Backups, backupToProto(info))
+	}
+	return resp, nil
+}
+
+func (s *AdminGRPCServer) RestoreBackup(
+	ctx context.Context,
+	req *blogpb.RestoreBackupRequest,
+) (*blogpb.Backup, error) {
+
+	info, err := s.backups.Restore(ctx, req.Name)
+	if err != nil {
+		return nil, adminError(err)
+	}
+	return backupToProto(info), nil
+}
+
+func backupToProto(info backup.Info) *blogpb.Backup {
+	return &blogpb.Backup{
+		Name:          info.Name,
+		CreatedAt:     timestamppb.New(info.CreatedAt),
+		PostCount:     int64(info.Posts),
+		SizeBytes:     info.Size,
+		Sha256:        info.SHA256,
+		FormatVersion: int32(info.Version),
+	}
+}
+
+// adminError maps backup and blog errors to status codes.
+func adminError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, backup.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, backup.ErrCorrupt):
+		code = codes.DataLoss
+	case errors.Is(err, backup.ErrIncompatible),
+		errors.Is(err, blog.ErrInvalidPost),
+		errors.Is(err, blog.ErrInvalidSlug),
+		errors.Is(err, blog.ErrSlugTaken),
+		errors.Is(err, blog.ErrInvalidContentFormat):
+		code = codes.FailedPrecondition
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+	return status.Error(code, err.Error())
+}
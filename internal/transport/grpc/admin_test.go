@@ -0,0 +1,50 @@
+package grpctransport
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-blog/proto/blogpb"
+)
+
+func TestBackupRestore(t *testing.T) {
+	conn, cleanup := setupTestGRPCServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	blogClient := blogpb.NewBlogServiceClient(conn)
+	admin := blogpb.NewAdminServiceClient(conn)
+
+	created, _ := blogClient.CreatePost(ctx, &blogpb.CreatePostRequest{Title: "kept"})
+	backup, err := admin.CreateBackup(ctx, &blogpb.CreateBackupRequest{})
+	if err != nil {
+		t.Fatalf("CreateBackup: %v", err)
+	}
+	if backup.PostCount != 1 || backup.Name == "" || len(backup.Sha256) != 64 || backup.FormatVersion != 1 {
+		t.Fatalf("unexpected backup %v", backup)
+	}
+
+	list, err := admin.ListBackups(ctx, &blogpb.ListBackupsRequest{})
+	if err != nil || len(list.Backups) != 1 || list.Backups[0].Name != backup.Name {
+		t.Fatalf("ListBackups: %v, %v", list, err)
+	}
+
+	blogClient.DeletePost(ctx, &blogpb.DeletePostRequest{PostId: created.Post[0].PostId})
+	blogClient.CreatePost(ctx, &blogpb.CreatePostRequest{Title: "dropped"})
+
+	if _, err := admin.RestoreBackup(ctx, &blogpb.RestoreBackupRequest{Name: backup.Name}); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	resp, _ := blogClient.ReadAll(ctx, &blogpb.ReadAllRequest{})
+	if len(resp.Post) != 1 || resp.Post[0].Title != "kept" {
+		t.Fatalf("expected the backed up post, got %v", resp.Post)
+	}
+
+	_, err = admin.RestoreBackup(ctx, &blogpb.RestoreBackupRequest{Name: "missing.backup"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+}
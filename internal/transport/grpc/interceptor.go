@@ -6,9 +6,18 @@ import (
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"grpc-blog/internal/infra/logging"
+	"grpc-blog/internal/infra/metrics"
 )
 
-func UnaryLoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+func UnaryLoggingInterceptor(logger *zap.Logger, payload logging.PayloadConfig) grpc.UnaryServerInterceptor {
+	payloads := newPayloadLogger(payload)
+
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -16,15 +25,166 @@ func UnaryLoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
 
+		log := logging.FromContext(ctx, logger)
+		payloads.log(log, "request", req)
+
 		start := time.Now()
 		resp, err := handler(ctx, req)
 
-		logger.Info("grpc request",
-			zap.String("method", info.FullMethod),
-			zap.Duration("latency", time.Since(start)),
-			zap.Error(err),
+		if err == nil {
+			payloads.log(log, "response", resp)
+		}
+
+		log.Info("grpc request",
+			append(callFields(ctx, info.FullMethod, err, time.Since(start)),
+				zap.Int("request_size", messageSize(req)),
+				zap.Int("response_size", messageSize(resp)),
+			)...,
 		)
 
 		return resp, err
 	}
 }
+
+// StreamLoggingInterceptor is the streaming counterpart of
+// UnaryLoggingInterceptor. It logs once per stream with message counts and
+// byte totals in each direction.
+func StreamLoggingInterceptor(logger *zap.Logger, payload logging.PayloadConfig) grpc.StreamServerInterceptor {
+	payloads := newPayloadLogger(payload)
+
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+
+		ctx := ss.Context()
+		stream := &loggingStream{
+			ServerStream: ss,
+			log:          logging.FromContext(ctx, logger),
+			payloads:     payloads,
+		}
+
+		start := time.Now()
+		err := handler(srv, stream)
+
+		stream.log.Info("grpc stream",
+			append(callFields(ctx, info.FullMethod, err, time.Since(start)),
+				zap.Int("msgs_received", stream.recvMsgs),
+				zap.Int("msgs_sent", stream.sentMsgs),
+				zap.Int("bytes_received", stream.recvBytes),
+				zap.Int("bytes_sent", stream.sentBytes),
+			)...,
+		)
+
+		return err
+	}
+}
+
+func UnaryMetricsInterceptor(m *metrics.RPCMetrics) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		m.Observe(info.FullMethod, status.Code(err), time.Since(start))
+
+		return resp, err
+	}
+}
+
+// StreamMetricsInterceptor records a stream once it ends, with its latency
+// covering the whole stream.
+func StreamMetricsInterceptor(m *metrics.RPCMetrics) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+
+		start := time.Now()
+		err := handler(srv, ss)
+
+		m.Observe(info.FullMethod, status.Code(err), time.Since(start))
+
+		return err
+	}
+}
+
+// callFields are the log fields shared by unary and stream calls.
+func callFields(ctx context.Context, method string, err error, latency time.Duration) []zap.Field {
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", status.Code(err).String()),
+		zap.Duration("latency", latency),
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			fields = append(fields, zap.String("peer", p.Addr.String()))
+		}
+		if principal := principalFromAuthInfo(p.AuthInfo); principal != "" {
+			fields = append(fields, zap.String("principal", principal))
+		}
+	}
+
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	return fields
+}
+
+// principalFromAuthInfo returns the subject of a verified TLS client
+// certificate, or "" for anonymous peers.
+func principalFromAuthInfo(info credentials.AuthInfo) string {
+	tlsInfo, ok := info.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+func messageSize(m interface{}) int {
+	if msg, ok := m.(proto.Message); ok {
+		return proto.Size(msg)
+	}
+	return 0
+}
+
+// loggingStream counts and optionally logs messages flowing through a stream.
+type loggingStream struct {
+	grpc.ServerStream
+
+	log      *zap.Logger
+	payloads *payloadLogger
+
+	recvMsgs, sentMsgs   int
+	recvBytes, sentBytes int
+}
+
+func (s *loggingStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sentMsgs++
+		s.sentBytes += messageSize(m)
+		s.payloads.log(s.log, "response", m)
+	}
+	return err
+}
+
+func (s *loggingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.recvMsgs++
+		s.recvBytes += messageSize(m)
+		s.payloads.log(s.log, "request", m)
+	}
+	return err
+}
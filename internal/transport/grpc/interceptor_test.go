@@ -3,15 +3,30 @@ package grpctransport
 import (
 	"context"
 	"errors"
+	"io"
+	"net"
+	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"grpc-blog/internal/infra/logging"
+	"grpc-blog/internal/infra/metrics"
+	"grpc-blog/proto/blogpb"
 )
 
 func TestUnaryLoggingInterceptor(t *testing.T) {
 	logger := zaptest.NewLogger(t)
-	interceptor := UnaryLoggingInterceptor(logger)
+	interceptor := UnaryLoggingInterceptor(logger, logging.PayloadConfig{})
 
 	handlerCalled := false
 
@@ -38,7 +53,7 @@ func TestUnaryLoggingInterceptor(t *testing.T) {
 
 func TestUnaryLoggingInterceptor_Error(t *testing.T) {
 	logger := zaptest.NewLogger(t)
-	interceptor := UnaryLoggingInterceptor(logger)
+	interceptor := UnaryLoggingInterceptor(logger, logging.PayloadConfig{})
 
 	expectedErr := errors.New("boom")
 
@@ -57,3 +72,200 @@ func TestUnaryLoggingInterceptor_Error(t *testing.T) {
 		t.Fatal("expected handler error to propagate")
 	}
 }
+
+func TestUnaryMetricsInterceptor(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := metrics.NewRPCMetrics(reg)
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	interceptor := UnaryMetricsInterceptor(m)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	}
+
+	_, err = interceptor(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test"},
+		handler,
+	)
+
+	if status.Code(err) != codes.NotFound {
+		t.Fatal("expected handler error to propagate")
+	}
+
+	count, err := testutil.GatherAndCount(reg, "grpc_server_handled_total")
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 handled series, got %d", count)
+	}
+}
+
+func TestStreamMetricsInterceptor(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := metrics.NewRPCMetrics(reg)
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	conn, cleanup := setupTestGRPCServer(t, grpc.StreamInterceptor(StreamMetricsInterceptor(m)))
+	defer cleanup()
+
+	stream, err := blogpb.NewBlogServiceClient(conn).ExportPosts(context.Background(), &blogpb.ExportPostsRequest{})
+	if err != nil {
+		t.Fatalf("ExportPosts failed: %v", err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("expected an empty export, got %v", err)
+	}
+
+	want := `
+# HELP grpc_server_handled_total Total number of RPCs completed on the server, by method and status code.
+# TYPE grpc_server_handled_total counter
+grpc_server_handled_total{code="OK",method="/blog.BlogService/ExportPosts"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "grpc_server_handled_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnaryLoggingInterceptor_Fields(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	interceptor := UnaryLoggingInterceptor(zap.New(core), logging.PayloadConfig{})
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4242},
+	})
+	req := &blogpb.ReadPostRequest{PostId: "abc"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	}
+
+	interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["peer"] != "10.0.0.1:4242" {
+		t.Fatalf("unexpected peer %v", fields["peer"])
+	}
+	if fields["code"] != "NotFound" {
+		t.Fatalf("unexpected code %v", fields["code"])
+	}
+	if fields["request_size"] != int64(proto.Size(req)) {
+		t.Fatalf("unexpected request size %v", fields["request_size"])
+	}
+}
+
+func TestUnaryLoggingInterceptor_Payload(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	interceptor := UnaryLoggingInterceptor(zap.New(core), logging.PayloadConfig{
+		Enabled:    true,
+		SampleRate: 1,
+		Redact:     []string{"content"},
+	})
+
+	req := &blogpb.CreatePostRequest{Title: "visible", Content: "secret"}
+	resp := &blogpb.PostResponse{Post: []*blogpb.BlogPost{{Title: "visible", Content: "secret"}}}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return resp, nil
+	}
+
+	interceptor(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+
+	payloads := logs.FilterMessage("grpc payload").All()
+	if len(payloads) != 2 {
+		t.Fatalf("expected request and response payloads, got %d", len(payloads))
+	}
+
+	for _, entry := range payloads {
+		body := entry.ContextMap()["payload"].(string)
+		if strings.Contains(body, "secret") || !strings.Contains(body, "visible") || !strings.Contains(body, "[REDACTED]") {
+			t.Fatalf("payload not redacted correctly: %s", body)
+		}
+	}
+
+	if req.Content != "secret" || resp.Post[0].Content != "secret" {
+		t.Fatal("redaction must not modify the original messages")
+	}
+}
+
+func TestUnaryLoggingInterceptor_PayloadRequiresDebug(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	interceptor := UnaryLoggingInterceptor(zap.New(core), logging.PayloadConfig{Enabled: true, SampleRate: 1})
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &blogpb.PostResponse{}, nil
+	}
+
+	interceptor(context.Background(), &blogpb.ReadAllRequest{}, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+
+	if logs.FilterMessage("grpc payload").Len() != 0 {
+		t.Fatal("payloads must not be logged above debug level")
+	}
+}
+
+type fakeMsgStream struct {
+	grpc.ServerStream
+	recv []proto.Message
+}
+
+func (s *fakeMsgStream) Context() context.Context { return context.Background() }
+
+func (s *fakeMsgStream) SendMsg(m interface{}) error { return nil }
+
+func (s *fakeMsgStream) RecvMsg(m interface{}) error {
+	if len(s.recv) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), s.recv[0])
+	s.recv = s.recv[1:]
+	return nil
+}
+
+func TestStreamLoggingInterceptor(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	interceptor := StreamLoggingInterceptor(zap.New(core), logging.PayloadConfig{})
+
+	stream := &fakeMsgStream{recv: []proto.Message{&blogpb.ReadPostRequest{PostId: "1"}}}
+	sent := &blogpb.BlogPost{Title: "streamed"}
+
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		var req blogpb.ReadPostRequest
+		for ss.RecvMsg(&req) == nil {
+		}
+		ss.SendMsg(sent)
+		ss.SendMsg(sent)
+		return nil
+	}
+
+	if err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/stream"}, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := logs.FilterMessage("grpc stream").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 stream log, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["msgs_received"] != int64(1) || fields["msgs_sent"] != int64(2) {
+		t.Fatalf("unexpected message counts: %v", fields)
+	}
+	if fields["bytes_sent"] != int64(2*proto.Size(sent)) {
+		t.Fatalf("unexpected bytes sent: %v", fields["bytes_sent"])
+	}
+	if fields["code"] != "OK" {
+		t.Fatalf("unexpected code %v", fields["code"])
+	}
+}
@@ -0,0 +1,117 @@
+package grpctransport
+
+import (
+	"math/rand/v2"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"grpc-blog/internal/infra/logging"
+)
+
+const redactedValue = "[REDACTED]"
+
+// payloadLogger writes sampled, redacted RPC messages at debug level.
+type payloadLogger struct {
+	enabled    bool
+	sampleRate float64
+	redact     map[protoreflect.Name]bool
+}
+
+func newPayloadLogger(cfg logging.PayloadConfig) *payloadLogger {
+	redact := make(map[protoreflect.Name]bool, len(cfg.Redact))
+	for _, name := range cfg.Redact {
+		redact[protoreflect.Name(name)] = true
+	}
+
+	return &payloadLogger{
+		enabled:    cfg.Enabled,
+		sampleRate: cfg.SampleRate,
+		redact:     redact,
+	}
+}
+
+// log writes m under the given direction ("request" or "response") if
+// payload logging is on, the logger is at debug level and m is sampled.
+func (p *payloadLogger) log(logger *zap.Logger, direction string, m interface{}) {
+	if !p.enabled || !logger.Core().Enabled(zapcore.DebugLevel) {
+		return
+	}
+
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return
+	}
+
+	if p.sampleRate < 1 && rand.Float64() >= p.sampleRate {
+		return
+	}
+
+	data, err := protojson.Marshal(p.redacted(msg))
+	if err != nil {
+		logger.Debug("grpc payload", zap.String("direction", direction), zap.Error(err))
+		return
+	}
+
+	logger.Debug("grpc payload",
+		zap.String("direction", direction),
+		zap.String("type", string(msg.ProtoReflect().Descriptor().FullName())),
+		zap.ByteString("payload", data),
+	)
+}
+
+// redacted returns a copy of msg with every configured field masked.
+func (p *payloadLogger) redacted(msg proto.Message) proto.Message {
+	if len(p.redact) == 0 {
+		return msg
+	}
+
+	clone := proto.Clone(msg)
+	p.redactMessage(clone.ProtoReflect())
+	return clone
+}
+
+func (p *payloadLogger) redactMessage(m protoreflect.Message) {
+	var masked []protoreflect.FieldDescriptor
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if p.redact[fd.Name()] {
+			masked = append(masked, fd)
+			return true
+		}
+
+		if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+			return true
+		}
+
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				p.redactMessage(list.Get(i).Message())
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					p.redactMessage(mv.Message())
+					return true
+				})
+			}
+		default:
+			p.redactMessage(v.Message())
+		}
+		return true
+	})
+
+	// Mutate outside Range: changing fields while iterating is undefined.
+	for _, fd := range masked {
+		if fd.Kind() == protoreflect.StringKind && !fd.IsList() {
+			m.Set(fd, protoreflect.ValueOfString(redactedValue))
+		} else {
+			m.Clear(fd)
+		}
+	}
+}
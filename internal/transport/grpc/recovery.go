@@ -0,0 +1,87 @@
+package grpctransport
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"grpc-blog/internal/infra/logging"
+	"grpc-blog/internal/infra/metrics"
+)
+
+// UnaryRecoveryInterceptor converts handler panics into codes.Internal errors.
+//
+// It should be the innermost interceptor so that logging and metrics
+// interceptors observe the resulting error.
+func UnaryRecoveryInterceptor(logger *zap.Logger, m *metrics.RPCMetrics) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(ctx, logger, m, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecoveryInterceptor is the streaming counterpart of UnaryRecoveryInterceptor.
+func StreamRecoveryInterceptor(logger *zap.Logger, m *metrics.RPCMetrics) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(ss.Context(), logger, m, info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// recoverPanic logs, traces and counts a recovered panic and returns the
+// error reported to the client. The panic value is not leaked to the caller.
+func recoverPanic(
+	ctx context.Context,
+	logger *zap.Logger,
+	m *metrics.RPCMetrics,
+	method string,
+	r interface{},
+) error {
+	stack := string(debug.Stack())
+
+	logging.FromContext(ctx, logger).Error("panic recovered",
+		zap.String("method", method),
+		zap.Any("panic", r),
+		zap.String("stack", stack),
+	)
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(fmt.Errorf("panic: %v", r), trace.WithAttributes(
+		semconv.ExceptionStacktrace(stack),
+	))
+	span.SetStatus(otelcodes.Error, "panic")
+
+	m.ObservePanic(method)
+
+	return status.Error(codes.Internal, "internal server error")
+}
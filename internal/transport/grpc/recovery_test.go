@@ -0,0 +1,113 @@
+package grpctransport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"grpc-blog/internal/infra/metrics"
+)
+
+func newRecoveryDeps(t *testing.T) (*zap.Logger, *observer.ObservedLogs, *prometheus.Registry, *metrics.RPCMetrics) {
+	t.Helper()
+
+	core, logs := observer.New(zap.ErrorLevel)
+	reg := prometheus.NewRegistry()
+	m, err := metrics.NewRPCMetrics(reg)
+	if err != nil {
+		t.Fatalf("failed to create metrics: %v", err)
+	}
+
+	return zap.New(core), logs, reg, m
+}
+
+func TestUnaryRecoveryInterceptor(t *testing.T) {
+	logger, logs, reg, m := newRecoveryDeps(t)
+	interceptor := UnaryRecoveryInterceptor(logger, m)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "rpc")
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-request-id", "req-1"))
+	ctx, _ = withRequestID(ctx, logger)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	}
+
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+	span.End()
+
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+
+	entries := logs.FilterMessage("panic recovered").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 panic log, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["method"] != "/test" || fields["request_id"] != "req-1" || fields["trace_id"] == nil || fields["stack"] == "" {
+		t.Fatalf("unexpected log fields: %v", fields)
+	}
+
+	ended := recorder.Ended()
+	if len(ended) != 1 || ended[0].Status().Code != otelcodes.Error || len(ended[0].Events()) == 0 {
+		t.Fatal("expected panic to be recorded on the span")
+	}
+
+	if got, _ := testutil.GatherAndCount(reg, "grpc_server_panics_total"); got != 1 {
+		t.Fatalf("expected panic counter to be incremented, got %d series", got)
+	}
+}
+
+func TestUnaryRecoveryInterceptor_NoPanic(t *testing.T) {
+	logger, logs, _, m := newRecoveryDeps(t)
+	interceptor := UnaryRecoveryInterceptor(logger, m)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+	if err != nil || resp != "ok" {
+		t.Fatalf("unexpected result: %v, %v", resp, err)
+	}
+	if logs.Len() != 0 {
+		t.Fatal("expected no logs without a panic")
+	}
+}
+
+func TestStreamRecoveryInterceptor(t *testing.T) {
+	logger, logs, _, m := newRecoveryDeps(t)
+	interceptor := StreamRecoveryInterceptor(logger, m)
+
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		panic("boom")
+	}
+
+	err := interceptor(
+		nil,
+		&contextStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/stream"},
+		handler,
+	)
+
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+	if logs.FilterField(zap.String("method", "/stream")).Len() != 1 {
+		t.Fatal("expected panic to be logged with method")
+	}
+}
@@ -0,0 +1,128 @@
+package grpctransport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"grpc-blog/internal/infra/logging"
+)
+
+// RequestIDHeader is the metadata key carrying the request ID in both
+// directions.
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLen bounds caller-supplied request IDs, which end up in every
+// log line of the request.
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID assigned by the request ID
+// interceptors, or "" outside of an RPC.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// UnaryRequestIDInterceptor accepts the caller's x-request-id, if it is at
+// most 128 characters of [A-Za-z0-9._:-], or generates one, echoes it in
+// the response headers, and stores a logger annotated with the request,
+// trace and span IDs in the context (see logging.FromContext).
+//
+// It must be the outermost interceptor so every later log line carries
+// the correlation fields.
+func UnaryRequestIDInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+
+		ctx, id := withRequestID(ctx, logger)
+		if err := grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id)); err != nil {
+			logging.FromContext(ctx, logger).Warn("failed to set request id header", zap.Error(err))
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRequestIDInterceptor is the streaming counterpart of
+// UnaryRequestIDInterceptor.
+func StreamRequestIDInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+
+		ctx, id := withRequestID(ss.Context(), logger)
+		if err := ss.SetHeader(metadata.Pairs(RequestIDHeader, id)); err != nil {
+			logging.FromContext(ctx, logger).Warn("failed to set request id header", zap.Error(err))
+		}
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func withRequestID(ctx context.Context, logger *zap.Logger) (context.Context, string) {
+	id := requestIDFromMetadata(ctx)
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	fields := []zap.Field{zap.String("request_id", id)}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields,
+			zap.String("trace_id", sc.TraceID().String()),
+			zap.String("span_id", sc.SpanID().String()),
+		)
+	}
+
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	ctx = logging.WithContext(ctx, logger.With(fields...))
+	return ctx, id
+}
+
+func requestIDFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(RequestIDHeader); len(values) > 0 && validRequestID(values[0]) {
+		return values[0]
+	}
+	return ""
+}
+
+// validRequestID reports whether a caller's request ID is safe to log and
+// echo back.
+func validRequestID(id string) bool {
+	if len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case r == '.' || r == '_' || r == ':' || r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// contextStream overrides the context of a wrapped ServerStream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
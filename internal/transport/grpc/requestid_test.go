@@ -0,0 +1,91 @@
+package grpctransport
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"grpc-blog/internal/infra/logging"
+	"grpc-blog/proto/blogpb"
+)
+
+func TestUnaryRequestIDInterceptor_Generates(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	interceptor := UnaryRequestIDInterceptor(zap.New(core))
+
+	var id string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		id = RequestIDFromContext(ctx)
+		logging.FromContext(ctx, zap.NewNop()).Info("inside handler")
+		return nil, nil
+	}
+
+	interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+
+	if id == "" {
+		t.Fatal("expected a generated request id")
+	}
+	if logs.FilterMessage("inside handler").FilterField(zap.String("request_id", id)).Len() != 1 {
+		t.Fatal("expected handler log to carry the request id")
+	}
+}
+
+func TestUnaryRequestIDInterceptor_RejectsUnsafe(t *testing.T) {
+	interceptor := UnaryRequestIDInterceptor(zap.NewNop())
+
+	kept := map[string]bool{
+		"req-42":                 true,
+		"svc.v1:trace_7":         true,
+		strings.Repeat("x", 128): true,
+		strings.Repeat("x", 129): false,
+		"a\nforged=log":          false,
+		"<script>":               false,
+	}
+	for callerID, want := range kept {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, callerID))
+
+		var id string
+		interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			id = RequestIDFromContext(ctx)
+			return nil, nil
+		})
+
+		if (id == callerID) != want || id == "" {
+			t.Errorf("caller id %q: got %q", callerID, id)
+		}
+	}
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+
+	conn, cleanup := setupTestGRPCServer(t,
+		grpc.ChainUnaryInterceptor(
+			UnaryRequestIDInterceptor(logger),
+			UnaryLoggingInterceptor(logger, logging.PayloadConfig{}),
+		),
+	)
+	defer cleanup()
+
+	client := blogpb.NewBlogServiceClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), RequestIDHeader, "req-42")
+	var header metadata.MD
+	if _, err := client.ReadAll(ctx, &blogpb.ReadAllRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	if got := header.Get(RequestIDHeader); len(got) != 1 || got[0] != "req-42" {
+		t.Fatalf("expected request id echoed in headers, got %v", got)
+	}
+
+	if logs.FilterMessage("grpc request").FilterField(zap.String("request_id", "req-42")).Len() != 1 {
+		t.Fatal("expected access log to carry the caller's request id")
+	}
+}
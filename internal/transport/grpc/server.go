@@ -2,6 +2,12 @@ package grpctransport
 
 import (
 	"context"
+	"errors"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"grpc-blog/internal/app/blog"
 	"grpc-blog/proto/blogpb"
@@ -27,9 +33,11 @@ func (s *BlogGRPCServer) CreatePost(
 		Author:          req.Author,
 		PublicationDate: req.PublicationDate,
 		Tags:            req.Tags,
+		Slug:            req.Slug,
+		ContentFormat:   req.ContentFormat,
 	}
 
-	created, err := s.service.CreatePost(post)
+	created, err := s.service.CreatePost(ctx, post)
 	if err != nil {
 		return &blogpb.PostResponse{
 			Error: err.Error(),
@@ -48,7 +56,7 @@ func (s *BlogGRPCServer) ReadPost(
 	req *blogpb.ReadPostRequest,
 ) (*blogpb.PostResponse, error) {
 
-	post, err := s.service.ReadPost(req.PostId)
+	post, err := s.service.ReadPost(ctx, req.PostId)
 	if err != nil {
 		return &blogpb.PostResponse{
 			Error: err.Error(),
@@ -67,7 +75,7 @@ func (s *BlogGRPCServer) ReadAll(
 	req *blogpb.ReadAllRequest,
 ) (*blogpb.PostResponse, error) {
 
-	posts, err := s.service.ReadAll()
+	page, err := s.service.ReadPage(ctx, int(req.PageSize), req.PageToken)
 	if err != nil {
 		return &blogpb.PostResponse{
 			Error: err.Error(),
@@ -75,7 +83,8 @@ func (s *BlogGRPCServer) ReadAll(
 	}
 
 	return &blogpb.PostResponse{
-		Post: posts,
+		Post:          page.Posts,
+		NextPageToken: page.NextPageToken,
 	}, nil
 }
 
@@ -85,13 +94,23 @@ func (s *BlogGRPCServer) UpdatePost(
 ) (*blogpb.PostResponse, error) {
 
 	post := &blogpb.BlogPost{
-		Title:   req.Title,
-		Content: req.Content,
-		Author:  req.Author,
-		Tags:    req.Tags,
+		Title:           req.Title,
+		Content:         req.Content,
+		Author:          req.Author,
+		Tags:            req.Tags,
+		Slug:            req.Slug,
+		ContentFormat:   req.ContentFormat,
+		PublicationDate: req.PublicationDate,
+	}
+	fields := req.GetUpdateMask().GetPaths()
+	if len(fields) == 0 {
+		fields = updateRequestFields
+		if req.PublicationDate != nil {
+			fields = append(slices.Clip(fields), "publication_date")
+		}
 	}
 
-	updated, err := s.service.UpdatePost(req.PostId, post)
+	updated, err := s.service.UpdatePost(ctx, req.PostId, post, fields...)
 	if err != nil {
 		return &blogpb.PostResponse{
 			Error: err.Error(),
@@ -105,12 +124,16 @@ func (s *BlogGRPCServer) UpdatePost(
 	}, nil
 }
 
+// updateRequestFields are the post fields an UpdatePostRequest replaces
+// when its update mask is empty, along with a publication date it sets.
+var updateRequestFields = []string{"title", "content", "author", "tags", "slug", "content_format"}
+
 func (s *BlogGRPCServer) DeletePost(
 	ctx context.Context,
 	req *blogpb.DeletePostRequest,
 ) (*blogpb.DeletePostResponse, error) {
 
-	err := s.service.DeletePost(req.PostId)
+	err := s.service.DeletePost(ctx, req.PostId)
 	if err != nil {
 		return &blogpb.DeletePostResponse{
 			Success: false,
@@ -122,3 +145,35 @@ func (s *BlogGRPCServer) DeletePost(
 		Success: true,
 	}, nil
 }
+
+// ExportPosts streams every post, ordered by ID. It reads the largest
+// pages the service allows, so only one page of posts is copied at a time.
+// Posts created or deleted during the export may or may not be included;
+// none is sent twice.
+func (s *BlogGRPCServer) ExportPosts(
+	req *blogpb.ExportPostsRequest,
+	stream grpc.ServerStreamingServer[blogpb.BlogPost],
+) error {
+
+	token := ""
+	for {
+		page, err := s.service.ReadPage(stream.Context(), blog.MaxPageSize, token)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err).Err()
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		for _, post := range page.Posts {
+			if err := stream.Send(post); err != nil {
+				return err
+			}
+		}
+
+		if page.NextPageToken == "" {
+			return nil
+		}
+		token = page.NextPageToken
+	}
+}
@@ -2,32 +2,39 @@ package grpctransport
 
 import (
 	"context"
+	"io"
 	"net"
+	"slices"
 	"testing"
 
 	"go.uber.org/zap/zaptest"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/test/bufconn"
 
+	"grpc-blog/internal/app/backup"
 	"grpc-blog/internal/app/blog"
 	"grpc-blog/proto/blogpb"
 )
 
 const bufSize = 1024 * 1024
 
-func setupTestGRPCServer(t *testing.T) (*grpc.ClientConn, func()) {
+func setupTestGRPCServer(t *testing.T, opts ...grpc.ServerOption) (*grpc.ClientConn, func()) {
 	t.Helper()
 
 	lis := bufconn.Listen(bufSize)
 
 	logger := zaptest.NewLogger(t)
-	service := blog.NewService(logger)
-	server := grpc.NewServer()
+	service := blog.NewService(logger, blog.NewMemoryStore())
+	server := grpc.NewServer(opts...)
 
 	blogpb.RegisterBlogServiceServer(
 		server,
 		NewBlogGRPCServer(service),
 	)
+	blogpb.RegisterAdminServiceServer(
+		server,
+		NewAdminGRPCServer(backup.NewManager(backup.Config{Dir: t.TempDir()}, logger, service)),
+	)
 
 	errCh := make(chan error, 1)
 
@@ -108,3 +115,43 @@ func TestReadPostNotFound(t *testing.T) {
 		t.Fatal("expected error for missing post")
 	}
 }
+
+func TestExportPosts(t *testing.T) {
+	conn, cleanup := setupTestGRPCServer(t)
+	defer cleanup()
+
+	client := blogpb.NewBlogServiceClient(conn)
+	ctx := context.Background()
+
+	// More than one page of the service.
+	want := blog.MaxPageSize + 5
+	for i := 0; i < want; i++ {
+		if _, err := client.CreatePost(ctx, &blogpb.CreatePostRequest{Title: "post"}); err != nil {
+			t.Fatalf("CreatePost failed: %v", err)
+		}
+	}
+
+	stream, err := client.ExportPosts(ctx, &blogpb.ExportPostsRequest{})
+	if err != nil {
+		t.Fatalf("ExportPosts failed: %v", err)
+	}
+
+	var ids []string
+	for {
+		post, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		ids = append(ids, post.PostId)
+	}
+
+	if len(ids) != want {
+		t.Fatalf("expected %d posts, got %d", want, len(ids))
+	}
+	if !slices.IsSorted(ids) || len(slices.Compact(ids)) != want {
+		t.Fatal("expected each post once, ordered by ID")
+	}
+}
@@ -0,0 +1,38 @@
+package httptransport
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RequireToken passes to next only requests carrying
+// "Authorization: Bearer <token>". Others are refused as Unauthenticated:
+// with a gRPC status for gRPC clients and 401 for everyone else.
+func RequireToken(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if token != "" && subtle.ConstantTimeCompare(got, want) == 1 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		const msg = "missing or invalid admin token"
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			// A trailers-only response, as a gRPC server sends for an error.
+			w.Header().Set("Content-Type", "application/grpc")
+			w.Header().Set("Grpc-Status", strconv.Itoa(int(codes.Unauthenticated)))
+			w.Header().Set("Grpc-Message", msg)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, status.New(codes.Unauthenticated, msg))
+	})
+}
@@ -0,0 +1,381 @@
+package httptransport
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"grpc-blog/internal/app/blog"
+	"grpc-blog/proto/blogpb"
+)
+
+// maxBodyBytes bounds request bodies accepted by the gateway.
+const maxBodyBytes = 1 << 20
+
+// Gateway exposes BlogService as RESTful JSON over HTTP.
+//
+// Routes:
+//
+//	GET    /v1/posts       ReadAll, paged with ?page_size=&page_token=
+//	POST   /v1/posts       CreatePost
+//	GET    /v1/posts/{id}  ReadPost
+//	PATCH  /v1/posts/{id}  UpdatePost, fields absent from the body are kept
+//	DELETE /v1/posts/{id}  DeletePost
+//	GET    /openapi.json   OpenAPI 3 document, see OpenAPI
+//	GET    /docs           HTML rendering of the document
+//
+// ExportPosts streams and is served over gRPC only.
+//
+// Every call runs through the same unary interceptors as the gRPC server,
+// with HTTP headers exposed as incoming metadata and headers set by
+// interceptors copied to the HTTP response.
+type Gateway struct {
+	server      blogpb.BlogServiceServer
+	interceptor grpc.UnaryServerInterceptor
+	mux         *http.ServeMux
+	spec        map[string]any
+}
+
+// NewGateway returns a Gateway dispatching to server through interceptors,
+// which run in order like grpc.ChainUnaryInterceptor.
+func NewGateway(server blogpb.BlogServiceServer, interceptors ...grpc.UnaryServerInterceptor) *Gateway {
+	g := &Gateway{
+		server:      server,
+		interceptor: chainUnary(interceptors),
+		mux:         http.NewServeMux(),
+		spec:        OpenAPI(),
+	}
+
+	handlers := map[string]http.HandlerFunc{
+		blogpb.BlogService_ReadAll_FullMethodName:    g.listPosts,
+		blogpb.BlogService_CreatePost_FullMethodName: g.createPost,
+		blogpb.BlogService_ReadPost_FullMethodName:   g.getPost,
+		blogpb.BlogService_UpdatePost_FullMethodName: g.updatePost,
+		blogpb.BlogService_DeletePost_FullMethodName: g.deletePost,
+	}
+	for _, rt := range routes {
+		g.mux.HandleFunc(rt.method+" "+rt.path, handlers[rt.rpc])
+	}
+	g.mux.HandleFunc("GET /openapi.json", g.openAPI)
+	g.mux.HandleFunc("GET /docs", g.docs)
+
+	return g
+}
+
+// route binds an HTTP method and path to a BlogService RPC. The table
+// drives both request routing and the OpenAPI document.
+type route struct {
+	method string
+	path   string
+	rpc    string
+	// status is the HTTP status of a successful response.
+	status int
+	// body is true when the request message is read from the body.
+	body bool
+}
+
+var routes = []route{
+	{http.MethodGet, "/v1/posts", blogpb.BlogService_ReadAll_FullMethodName, http.StatusOK, false},
+	{http.MethodPost, "/v1/posts", blogpb.BlogService_CreatePost_FullMethodName, http.StatusCreated, true},
+	{http.MethodGet, "/v1/posts/{id}", blogpb.BlogService_ReadPost_FullMethodName, http.StatusOK, false},
+	{http.MethodPatch, "/v1/posts/{id}", blogpb.BlogService_UpdatePost_FullMethodName, http.StatusOK, true},
+	{http.MethodDelete, "/v1/posts/{id}", blogpb.BlogService_DeletePost_FullMethodName, http.StatusOK, false},
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// listPosts takes page_size and page_token from the query string.
+func (g *Gateway) listPosts(w http.ResponseWriter, r *http.Request) {
+	req := &blogpb.ReadAllRequest{PageToken: r.URL.Query().Get("page_token")}
+	if size := r.URL.Query().Get("page_size"); size != "" {
+		n, err := strconv.ParseInt(size, 10, 32)
+		if err != nil || n < 0 {
+			writeError(w, status.Newf(codes.InvalidArgument, "invalid page_size %q", size))
+			return
+		}
+		req.PageSize = int32(n)
+	}
+
+	g.call(w, r, blogpb.BlogService_ReadAll_FullMethodName, req, http.StatusOK,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.server.ReadAll(ctx, req.(*blogpb.ReadAllRequest))
+		})
+}
+
+func (g *Gateway) createPost(w http.ResponseWriter, r *http.Request) {
+	req := &blogpb.CreatePostRequest{}
+	if !decodeBody(w, r, req) {
+		return
+	}
+
+	g.call(w, r, blogpb.BlogService_CreatePost_FullMethodName, req, http.StatusCreated,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.server.CreatePost(ctx, req.(*blogpb.CreatePostRequest))
+		})
+}
+
+func (g *Gateway) getPost(w http.ResponseWriter, r *http.Request) {
+	req := &blogpb.ReadPostRequest{PostId: r.PathValue("id")}
+
+	g.call(w, r, blogpb.BlogService_ReadPost_FullMethodName, req, http.StatusOK,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.server.ReadPost(ctx, req.(*blogpb.ReadPostRequest))
+		})
+}
+
+func (g *Gateway) updatePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	req := &blogpb.UpdatePostRequest{}
+	if err := protojson.Unmarshal(body, req); err != nil {
+		writeError(w, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+	req.PostId = r.PathValue("id")
+
+	present, err := jsonKeys(body)
+	if err != nil {
+		writeError(w, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+	if !present["updateMask"] && !present["update_mask"] {
+		req.UpdateMask = &fieldmaskpb.FieldMask{Paths: presentFields(req, present)}
+	}
+
+	g.call(w, r, blogpb.BlogService_UpdatePost_FullMethodName, req, http.StatusOK,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			update := req.(*blogpb.UpdatePostRequest)
+
+			// An empty mask would replace every field; nothing is to change.
+			if len(update.GetUpdateMask().GetPaths()) == 0 {
+				return g.server.ReadPost(ctx, &blogpb.ReadPostRequest{PostId: update.PostId})
+			}
+			return g.server.UpdatePost(ctx, update)
+		})
+}
+
+func (g *Gateway) deletePost(w http.ResponseWriter, r *http.Request) {
+	req := &blogpb.DeletePostRequest{PostId: r.PathValue("id")}
+
+	g.call(w, r, blogpb.BlogService_DeletePost_FullMethodName, req, http.StatusOK,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.server.DeletePost(ctx, req.(*blogpb.DeletePostRequest))
+		})
+}
+
+// call runs handler through the interceptor chain as method and writes the
+// response, or the translated error, as JSON.
+func (g *Gateway) call(
+	w http.ResponseWriter,
+	r *http.Request,
+	method string,
+	req proto.Message,
+	successStatus int,
+	handler grpc.UnaryHandler,
+) {
+	ctx, stream := incomingContext(r.Context(), method, r.Header, r.RemoteAddr)
+
+	resp, err := g.interceptor(ctx, req, &grpc.UnaryServerInfo{Server: g.server, FullMethod: method}, handler)
+
+	stream.copyHeader(w.Header())
+
+	if err != nil {
+		writeError(w, status.Convert(err))
+		return
+	}
+
+	// BlogService reports business errors in-band.
+	if e, ok := resp.(interface{ GetError() string }); ok && e.GetError() != "" {
+		writeError(w, status.New(inBandCode(e.GetError()), e.GetError()))
+		return
+	}
+
+	writeJSON(w, successStatus, resp.(proto.Message))
+}
+
+// incomingContext exposes request headers as gRPC incoming metadata and the
+// remote address as the peer, as the gRPC server would. Headers set through
+// grpc.SetHeader while handling method are captured by the returned stream.
+func incomingContext(ctx context.Context, method string, header http.Header, remoteAddr string) (context.Context, *headerStream) {
+	md := metadata.MD{}
+	for key, values := range header {
+		md.Append(strings.ToLower(key), values...)
+	}
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	if addr, err := net.ResolveTCPAddr("tcp", remoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+
+	stream := &headerStream{method: method}
+	return grpc.NewContextWithServerTransportStream(ctx, stream), stream
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, m proto.Message) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err == nil {
+		err = protojson.Unmarshal(body, m)
+	}
+	if err != nil {
+		writeError(w, status.New(codes.InvalidArgument, err.Error()))
+		return false
+	}
+	return true
+}
+
+// jsonKeys returns the top-level keys of a JSON object.
+func jsonKeys(body []byte) (map[string]bool, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(fields))
+	for k := range fields {
+		keys[k] = true
+	}
+	return keys, nil
+}
+
+// presentFields returns the proto names of the fields of m present in a
+// body with the given keys, in JSON (camelCase) or proto (snake_case) form.
+// The post ID, taken from the path, and the update mask are left out.
+func presentFields(m proto.Message, present map[string]bool) []string {
+	fields := m.ProtoReflect().Descriptor().Fields()
+
+	var names []string
+	for key := range present {
+		fd := fields.ByJSONName(key)
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(key))
+		}
+		if fd == nil || fd.Name() == "post_id" || fd.Name() == "update_mask" {
+			continue
+		}
+		names = append(names, string(fd.Name()))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// inBandCode maps a BlogService in-band error to a status code. Errors
+// not known to be the caller's fault are Internal.
+func inBandCode(msg string) codes.Code {
+	switch msg {
+	case blog.ErrNotFound.Error():
+		return codes.NotFound
+	case blog.ErrSlugTaken.Error():
+		return codes.AlreadyExists
+	case context.Canceled.Error():
+		return codes.Canceled
+	case context.DeadlineExceeded.Error():
+		return codes.DeadlineExceeded
+	}
+	for _, err := range invalidArgumentErrors {
+		// Some are wrapped with details after a colon.
+		if msg == err.Error() || strings.HasPrefix(msg, err.Error()+":") {
+			return codes.InvalidArgument
+		}
+	}
+	return codes.Internal
+}
+
+// invalidArgumentErrors are the BlogService errors caused by the request.
+var invalidArgumentErrors = []error{
+	blog.ErrInvalidPageToken,
+	blog.ErrInvalidSlug,
+	blog.ErrInvalidContentFormat,
+	blog.ErrInvalidUpdateMask,
+}
+
+// errorBody is the JSON shape of every gateway error.
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeError(w http.ResponseWriter, st *status.Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(HTTPStatusFromCode(st.Code()))
+	json.NewEncoder(w).Encode(errorBody{Code: st.Code().String(), Message: st.Message()})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, m proto.Message) {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		writeError(w, status.New(codes.Internal, err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(statusCode)
+	w.Write(data)
+}
+
+// headerStream captures headers set by interceptors via grpc.SetHeader.
+type headerStream struct {
+	method string
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string { return s.method }
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+func (s *headerStream) SetTrailer(md metadata.MD) error { return nil }
+
+// copyHeader adds the captured headers to h.
+func (s *headerStream) copyHeader(h http.Header) {
+	for key, values := range s.header {
+		for _, v := range values {
+			h.Add(key, v)
+		}
+	}
+}
+
+// chainUnary composes interceptors so the first one is outermost.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,298 @@
+package httptransport
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"grpc-blog/internal/app/blog"
+	"grpc-blog/internal/infra/logging"
+	grpctransport "grpc-blog/internal/transport/grpc"
+	"grpc-blog/proto/blogpb"
+)
+
+func newTestGateway(t *testing.T, interceptors ...grpc.UnaryServerInterceptor) *httptest.Server {
+	t.Helper()
+
+	service := blog.NewService(zaptest.NewLogger(t), blog.NewMemoryStore())
+	server := httptest.NewServer(NewGateway(grpctransport.NewBlogGRPCServer(service), interceptors...))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func do(t *testing.T, method, url, body string, header ...string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+func decodePost(t *testing.T, resp *http.Response) *blogpb.BlogPost {
+	t.Helper()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+
+	var out blogpb.PostResponse
+	if err := protojson.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(out.Post) != 1 {
+		t.Fatalf("expected one post, got %d", len(out.Post))
+	}
+	return out.Post[0]
+}
+
+func TestGatewayCRUD(t *testing.T) {
+	server := newTestGateway(t)
+
+	resp := do(t, http.MethodPost, server.URL+"/v1/posts",
+		`{"title":"hello","content":"body","author":"me","tags":["go"],"publicationDate":"2024-01-02T00:00:00Z"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	created := decodePost(t, resp)
+
+	resp = do(t, http.MethodGet, server.URL+"/v1/posts/"+created.PostId, "")
+	if resp.StatusCode != http.StatusOK || decodePost(t, resp).Title != "hello" {
+		t.Fatal("expected to read the created post")
+	}
+
+	resp = do(t, http.MethodPatch, server.URL+"/v1/posts/"+created.PostId, `{"title":"patched"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	patched := decodePost(t, resp)
+	if patched.Title != "patched" || patched.Content != "body" || patched.Author != "me" || len(patched.Tags) != 1 || patched.PublicationDate == nil {
+		t.Fatalf("PATCH should keep absent fields, got %v", patched)
+	}
+
+	resp = do(t, http.MethodGet, server.URL+"/v1/posts", "")
+	if resp.StatusCode != http.StatusOK || decodePost(t, resp).Title != "patched" {
+		t.Fatal("expected list to contain the patched post")
+	}
+
+	resp = do(t, http.MethodDelete, server.URL+"/v1/posts/"+created.PostId, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	resp = do(t, http.MethodGet, server.URL+"/v1/posts/"+created.PostId, "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", resp.StatusCode)
+	}
+}
+
+func TestGatewayErrors(t *testing.T) {
+	server := newTestGateway(t)
+
+	resp := do(t, http.MethodPost, server.URL+"/v1/posts", `{"title":`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for malformed JSON, got %d", resp.StatusCode)
+	}
+
+	var body errorBody
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body.Code != codes.InvalidArgument.String() {
+		t.Fatalf("unexpected error body %+v", body)
+	}
+
+	resp = do(t, http.MethodPatch, server.URL+"/v1/posts/missing", `{"title":"x"}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing post, got %d", resp.StatusCode)
+	}
+
+	resp = do(t, http.MethodPatch, server.URL+"/v1/posts/missing", `{}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for an empty PATCH of a missing post, got %d", resp.StatusCode)
+	}
+
+	resp = do(t, http.MethodPut, server.URL+"/v1/posts/missing", `{}`)
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for unsupported method, got %d", resp.StatusCode)
+	}
+}
+
+func TestGatewaySlugs(t *testing.T) {
+	server := newTestGateway(t)
+
+	resp := do(t, http.MethodPost, server.URL+"/v1/posts", `{"title":"a","slug":"hello"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	created := decodePost(t, resp)
+
+	resp = do(t, http.MethodPost, server.URL+"/v1/posts", `{"title":"b","slug":"hello"}`)
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 for a taken slug, got %d", resp.StatusCode)
+	}
+	resp = do(t, http.MethodPost, server.URL+"/v1/posts", `{"title":"b","slug":"Hello World"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid slug, got %d", resp.StatusCode)
+	}
+
+	// PATCH keeps the slug unless the body sets it.
+	resp = do(t, http.MethodPatch, server.URL+"/v1/posts/"+created.PostId, `{"title":"c"}`)
+	if resp.StatusCode != http.StatusOK || decodePost(t, resp).Slug != "hello" {
+		t.Fatal("expected PATCH to keep the slug")
+	}
+	resp = do(t, http.MethodPatch, server.URL+"/v1/posts/"+created.PostId, `{"slug":""}`)
+	if resp.StatusCode != http.StatusOK || decodePost(t, resp).Slug != "" {
+		t.Fatal("expected PATCH to clear the slug")
+	}
+}
+
+func TestGatewayContentFormat(t *testing.T) {
+	server := newTestGateway(t)
+
+	resp := do(t, http.MethodPost, server.URL+"/v1/posts", `{"content":"**hi**","contentFormat":"CONTENT_FORMAT_MARKDOWN"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	created := decodePost(t, resp)
+	if created.RenderedHtml != "<p><strong>hi</strong></p>\n" {
+		t.Fatalf("unexpected rendered HTML %q", created.RenderedHtml)
+	}
+
+	// PATCH keeps the format unless the body sets it, under either name.
+	resp = do(t, http.MethodPatch, server.URL+"/v1/posts/"+created.PostId, `{"content":"*hi*"}`)
+	if post := decodePost(t, resp); post.RenderedHtml != "<p><em>hi</em></p>\n" {
+		t.Fatalf("expected PATCH to keep the format, got %q", post.RenderedHtml)
+	}
+	resp = do(t, http.MethodPatch, server.URL+"/v1/posts/"+created.PostId, `{"content_format":"CONTENT_FORMAT_HTML"}`)
+	if post := decodePost(t, resp); post.ContentFormat != blogpb.ContentFormat_CONTENT_FORMAT_HTML {
+		t.Fatalf("expected PATCH to set the format, got %v", post.ContentFormat)
+	}
+
+	resp = do(t, http.MethodPost, server.URL+"/v1/posts", `{"contentFormat":42}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown format, got %d", resp.StatusCode)
+	}
+}
+
+func TestGatewayPaging(t *testing.T) {
+	server := newTestGateway(t)
+	for i := 0; i < 3; i++ {
+		do(t, http.MethodPost, server.URL+"/v1/posts", `{"title":"p"}`)
+	}
+
+	resp := do(t, http.MethodGet, server.URL+"/v1/posts?page_size=2", "")
+	var page blogpb.PostResponse
+	raw, _ := io.ReadAll(resp.Body)
+	if err := protojson.Unmarshal(raw, &page); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(page.Post) != 2 || page.NextPageToken == "" {
+		t.Fatalf("expected two posts and a token, got %s", raw)
+	}
+
+	resp = do(t, http.MethodGet, server.URL+"/v1/posts?page_size=2&page_token="+page.NextPageToken, "")
+	raw, _ = io.ReadAll(resp.Body)
+	if err := protojson.Unmarshal(raw, &page); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(page.Post) != 1 || page.NextPageToken != "" {
+		t.Fatalf("expected the last post and no token, got %s", raw)
+	}
+
+	for _, query := range []string{"page_size=-1", "page_size=ten", "page_token=%21%21"} {
+		if resp := do(t, http.MethodGet, server.URL+"/v1/posts?"+query, ""); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestGatewaySharesInterceptors(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+
+	deny := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if info.FullMethod == blogpb.BlogService_DeletePost_FullMethodName {
+			return nil, status.Error(codes.PermissionDenied, "read only")
+		}
+		return handler(ctx, req)
+	}
+
+	server := newTestGateway(t,
+		grpctransport.UnaryRequestIDInterceptor(logger),
+		grpctransport.UnaryLoggingInterceptor(logger, logging.PayloadConfig{}),
+		deny,
+	)
+
+	resp := do(t, http.MethodGet, server.URL+"/v1/posts", "", "X-Request-Id", "req-7")
+	if got := resp.Header.Get("X-Request-Id"); got != "req-7" {
+		t.Fatalf("expected request id echoed, got %q", got)
+	}
+
+	entries := logs.FilterMessage("grpc request").FilterField(zap.String("request_id", "req-7")).All()
+	if len(entries) != 1 || entries[0].ContextMap()["method"] != blogpb.BlogService_ReadAll_FullMethodName {
+		t.Fatal("expected gateway call to be logged as the gRPC method")
+	}
+
+	resp = do(t, http.MethodDelete, server.URL+"/v1/posts/any", "")
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 from interceptor, got %d", resp.StatusCode)
+	}
+}
+
+func TestInBandCode(t *testing.T) {
+	cases := map[string]codes.Code{
+		blog.ErrNotFound.Error():                            codes.NotFound,
+		blog.ErrSlugTaken.Error():                           codes.AlreadyExists,
+		blog.ErrInvalidSlug.Error():                         codes.InvalidArgument,
+		blog.ErrInvalidUpdateMask.Error() + `: unknown "x"`: codes.InvalidArgument,
+		context.DeadlineExceeded.Error():                    codes.DeadlineExceeded,
+		"disk full":                                         codes.Internal,
+	}
+	for msg, want := range cases {
+		if got := inBandCode(msg); got != want {
+			t.Errorf("inBandCode(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
+
+func TestHTTPStatusFromCode(t *testing.T) {
+	cases := map[codes.Code]int{
+		codes.OK:               http.StatusOK,
+		codes.InvalidArgument:  http.StatusBadRequest,
+		codes.NotFound:         http.StatusNotFound,
+		codes.Unauthenticated:  http.StatusUnauthorized,
+		codes.PermissionDenied: http.StatusForbidden,
+		codes.Unavailable:      http.StatusServiceUnavailable,
+		codes.Internal:         http.StatusInternalServerError,
+	}
+
+	for code, want := range cases {
+		if got := HTTPStatusFromCode(code); got != want {
+			t.Errorf("%v: expected %d, got %d", code, want, got)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package httptransport
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"grpc-blog/proto/blogpb"
+)
+
+// docsPage renders /openapi.json in the browser without external assets.
+//
+//go:embed docs.html
+var docsPage []byte
+
+// pathParams maps gateway path parameters to the request field they fill.
+var pathParams = map[string]protoreflect.Name{
+	"id": "post_id",
+}
+
+// OpenAPI returns an OpenAPI 3 document describing the gateway routes.
+//
+// Operations and schemas are derived from the BlogService descriptors, so
+// the document follows blog.proto without a separate generation step.
+// Schemas use the proto3 JSON mapping that the gateway speaks.
+func OpenAPI() map[string]any {
+	svc := blogpb.File_proto_blog_proto.Services().ByName("BlogService")
+	schemas := map[string]any{
+		"Error": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"code":    map[string]any{"type": "string"},
+				"message": map[string]any{"type": "string"},
+			},
+		},
+	}
+
+	paths := map[string]any{}
+	for _, rt := range routes {
+		name := rt.rpc[strings.LastIndex(rt.rpc, "/")+1:]
+		md := svc.Methods().ByName(protoreflect.Name(name))
+
+		op := map[string]any{
+			"operationId": string(md.Name()),
+			"summary":     string(md.FullName()),
+			"tags":        []string{string(svc.Name())},
+			"responses": map[string]any{
+				strconv.Itoa(rt.status): map[string]any{
+					"description": "OK",
+					"content":     jsonContent(schemaRef(md.Output(), schemas)),
+				},
+				"default": map[string]any{
+					"description": "Error",
+					"content":     jsonContent(map[string]any{"$ref": "#/components/schemas/Error"}),
+				},
+			},
+		}
+
+		bound := map[protoreflect.Name]bool{}
+		var params []any
+		for param, field := range pathParams {
+			if !strings.Contains(rt.path, "{"+param+"}") {
+				continue
+			}
+			bound[field] = true
+			params = append(params, map[string]any{
+				"name":        param,
+				"in":          "path",
+				"required":    true,
+				"description": "Sets " + string(md.Input().FullName()) + "." + string(field) + ".",
+				"schema":      map[string]any{"type": "string"},
+			})
+		}
+		if !rt.body {
+			// The other fields of body-less requests come from the query.
+			fields := md.Input().Fields()
+			for i := 0; i < fields.Len(); i++ {
+				fd := fields.Get(i)
+				if bound[fd.Name()] {
+					continue
+				}
+				params = append(params, map[string]any{
+					"name":   string(fd.Name()),
+					"in":     "query",
+					"schema": fieldSchema(fd, schemas),
+				})
+			}
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if rt.body {
+			body := schemaRef(md.Input(), schemas)
+			if len(bound) > 0 {
+				// Fields taken from the path are not read from the body.
+				body = messageSchema(md.Input(), schemas, bound)
+			}
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(body),
+			}
+		}
+
+		item, _ := paths[rt.path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[rt.path] = item
+		}
+		item[strings.ToLower(rt.method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   string(svc.FullName()),
+			"version": "v1",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+}
+
+func (g *Gateway) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(g.spec)
+}
+
+func (g *Gateway) docs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(docsPage)
+}
+
+// schemaRef registers md under components and returns a reference to it.
+func schemaRef(md protoreflect.MessageDescriptor, schemas map[string]any) map[string]any {
+	if s, ok := wellKnownSchema(md); ok {
+		return s
+	}
+
+	name := string(md.Name())
+	if _, ok := schemas[name]; !ok {
+		schemas[name] = nil // break cycles before recursing
+		schemas[name] = messageSchema(md, schemas, nil)
+	}
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// messageSchema describes md as a JSON object, leaving out skipped fields.
+func messageSchema(md protoreflect.MessageDescriptor, schemas map[string]any, skip map[protoreflect.Name]bool) map[string]any {
+	props := map[string]any{}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if skip[fd.Name()] {
+			continue
+		}
+		props[fd.JSONName()] = fieldSchema(fd, schemas)
+	}
+	return map[string]any{"type": "object", "properties": props}
+}
+
+func fieldSchema(fd protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	switch {
+	case fd.IsMap():
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": singularSchema(fd.MapValue(), schemas),
+		}
+	case fd.IsList():
+		return map[string]any{"type": "array", "items": singularSchema(fd, schemas)}
+	default:
+		return singularSchema(fd, schemas)
+	}
+}
+
+// singularSchema follows the proto3 JSON mapping: 64-bit integers are
+// strings and bytes are base64.
+func singularSchema(fd protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return schemaRef(fd.Message(), schemas)
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// wellKnownSchema covers well-known types with a special JSON form.
+func wellKnownSchema(md protoreflect.MessageDescriptor) (map[string]any, bool) {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return map[string]any{"type": "string", "format": "date-time"}, true
+	case "google.protobuf.Duration":
+		return map[string]any{"type": "string"}, true
+	case "google.protobuf.FieldMask":
+		// Comma-separated field names.
+		return map[string]any{"type": "string"}, true
+	}
+	return nil, false
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
@@ -0,0 +1,180 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"grpc-blog/proto/blogpb"
+)
+
+func fetchSpec(t *testing.T) map[string]any {
+	t.Helper()
+
+	server := newTestGateway(t)
+	resp := do(t, http.MethodGet, server.URL+"/openapi.json", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var spec map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatalf("failed to decode spec: %v", err)
+	}
+	return spec
+}
+
+func TestOpenAPICoversEveryRPC(t *testing.T) {
+	spec := fetchSpec(t)
+
+	operations := map[string]map[string]any{}
+	for _, item := range spec["paths"].(map[string]any) {
+		for _, op := range item.(map[string]any) {
+			op := op.(map[string]any)
+			operations[op["operationId"].(string)] = op
+		}
+	}
+
+	// Streaming RPCs are served over gRPC only.
+	var unary []protoreflect.MethodDescriptor
+	methods := blogpb.File_proto_blog_proto.Services().ByName("BlogService").Methods()
+	for i := 0; i < methods.Len(); i++ {
+		if md := methods.Get(i); !md.IsStreamingClient() && !md.IsStreamingServer() {
+			unary = append(unary, md)
+		}
+	}
+	if len(operations) != len(unary) {
+		t.Fatalf("expected %d operations, got %d", len(unary), len(operations))
+	}
+
+	for _, md := range unary {
+		op, ok := operations[string(md.Name())]
+		if !ok {
+			t.Errorf("%s missing from the document", md.FullName())
+			continue
+		}
+
+		ref := responseRef(t, op)
+		if ref != "#/components/schemas/"+string(md.Output().Name()) {
+			t.Errorf("%s: unexpected response schema %q", md.Name(), ref)
+		}
+	}
+}
+
+func TestOpenAPISchemasMatchMessages(t *testing.T) {
+	schemas := fetchSpec(t)["components"].(map[string]any)["schemas"].(map[string]any)
+
+	methods := blogpb.File_proto_blog_proto.Services().ByName("BlogService").Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i).Output()
+
+		schema, ok := schemas[string(md.Name())].(map[string]any)
+		if !ok {
+			t.Errorf("schema %s missing", md.Name())
+			continue
+		}
+		assertFields(t, md, schema)
+	}
+
+	post := schemas["BlogPost"].(map[string]any)
+	assertFields(t, (&blogpb.BlogPost{}).ProtoReflect().Descriptor(), post)
+
+	props := post["properties"].(map[string]any)
+	if got := props["publicationDate"].(map[string]any)["format"]; got != "date-time" {
+		t.Errorf("expected timestamp as date-time, got %v", got)
+	}
+	if got := props["tags"].(map[string]any)["items"].(map[string]any)["type"]; got != "string" {
+		t.Errorf("expected tags as string array, got %v", got)
+	}
+}
+
+func TestOpenAPIPathParamsLeaveBody(t *testing.T) {
+	spec := fetchSpec(t)
+
+	patch := spec["paths"].(map[string]any)["/v1/posts/{id}"].(map[string]any)["patch"].(map[string]any)
+	params := patch["parameters"].([]any)
+	if len(params) != 1 || params[0].(map[string]any)["name"] != "id" {
+		t.Fatalf("expected id path parameter, got %v", params)
+	}
+
+	body := patch["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	props := body["properties"].(map[string]any)
+	if _, ok := props["postId"]; ok {
+		t.Fatal("postId is bound from the path and should not be in the body")
+	}
+	if _, ok := props["title"]; !ok {
+		t.Fatal("expected title in the PATCH body")
+	}
+}
+
+func TestOpenAPIQueryParams(t *testing.T) {
+	spec := fetchSpec(t)
+
+	list := spec["paths"].(map[string]any)["/v1/posts"].(map[string]any)["get"].(map[string]any)
+	params, _ := list["parameters"].([]any)
+
+	names := map[string]string{}
+	for _, p := range params {
+		p := p.(map[string]any)
+		names[p["name"].(string)] = p["in"].(string)
+	}
+	if names["page_size"] != "query" || names["page_token"] != "query" || len(names) != 2 {
+		t.Fatalf("expected page_size and page_token query parameters, got %v", params)
+	}
+
+	get := spec["paths"].(map[string]any)["/v1/posts/{id}"].(map[string]any)["get"].(map[string]any)
+	if params := get["parameters"].([]any); len(params) != 1 {
+		t.Fatalf("expected only the id parameter on ReadPost, got %v", params)
+	}
+}
+
+func TestDocsPage(t *testing.T) {
+	server := newTestGateway(t)
+
+	resp := do(t, http.MethodGet, server.URL+"/docs", "")
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "/openapi.json") {
+		t.Fatalf("unexpected docs response %d", resp.StatusCode)
+	}
+}
+
+func responseRef(t *testing.T, op map[string]any) string {
+	t.Helper()
+
+	for code, resp := range op["responses"].(map[string]any) {
+		if code == "default" {
+			continue
+		}
+		schema := resp.(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+		ref, _ := schema.(map[string]any)["$ref"].(string)
+		return ref
+	}
+	t.Fatal("no success response")
+	return ""
+}
+
+// assertFields checks that schema lists exactly the JSON names of md's fields.
+func assertFields(t *testing.T, md protoreflect.MessageDescriptor, schema map[string]any) {
+	t.Helper()
+
+	props := schema["properties"].(map[string]any)
+	fields := md.Fields()
+	if len(props) != fields.Len() {
+		t.Errorf("%s: expected %d properties, got %d", md.Name(), fields.Len(), len(props))
+	}
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		prop, ok := props[fd.JSONName()].(map[string]any)
+		if !ok {
+			t.Errorf("%s: missing property %s", md.Name(), fd.JSONName())
+			continue
+		}
+		if fd.IsList() && prop["type"] != "array" {
+			t.Errorf("%s.%s: expected array, got %v", md.Name(), fd.Name(), prop["type"])
+		}
+	}
+}
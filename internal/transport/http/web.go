@@ -0,0 +1,109 @@
+package httptransport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	connectcors "connectrpc.com/cors"
+	"github.com/rs/cors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"grpc-blog/internal/config"
+	grpctransport "grpc-blog/internal/transport/grpc"
+	"grpc-blog/proto/blogpb"
+)
+
+// NewWebHandler serves BlogService over the Connect, gRPC-Web and gRPC
+// protocols for browser clients using generated Connect or gRPC-Web stubs.
+//
+// Procedures keep their gRPC paths, e.g. /blog.BlogService/CreatePost, and
+// every call runs through interceptors like the gateway does. Business
+// errors stay in-band, exactly as native gRPC clients see them. The
+// streaming ExportPosts is served over native gRPC only.
+func NewWebHandler(server blogpb.BlogServiceServer, interceptors ...grpc.UnaryServerInterceptor) http.Handler {
+	interceptor := chainUnary(interceptors)
+	opts := []connect.HandlerOption{connect.WithReadMaxBytes(maxBodyBytes)}
+
+	mux := http.NewServeMux()
+	mux.Handle(blogpb.BlogService_CreatePost_FullMethodName,
+		unaryHandler(blogpb.BlogService_CreatePost_FullMethodName, server, interceptor, server.CreatePost, opts))
+	mux.Handle(blogpb.BlogService_ReadPost_FullMethodName,
+		unaryHandler(blogpb.BlogService_ReadPost_FullMethodName, server, interceptor, server.ReadPost, opts))
+	mux.Handle(blogpb.BlogService_UpdatePost_FullMethodName,
+		unaryHandler(blogpb.BlogService_UpdatePost_FullMethodName, server, interceptor, server.UpdatePost, opts))
+	mux.Handle(blogpb.BlogService_DeletePost_FullMethodName,
+		unaryHandler(blogpb.BlogService_DeletePost_FullMethodName, server, interceptor, server.DeletePost, opts))
+	mux.Handle(blogpb.BlogService_ReadAll_FullMethodName,
+		unaryHandler(blogpb.BlogService_ReadAll_FullMethodName, server, interceptor, server.ReadAll, opts))
+
+	return mux
+}
+
+// unaryHandler adapts one BlogService method to a Connect handler.
+func unaryHandler[Req, Res any](
+	method string,
+	server blogpb.BlogServiceServer,
+	interceptor grpc.UnaryServerInterceptor,
+	call func(context.Context, *Req) (*Res, error),
+	opts []connect.HandlerOption,
+) http.Handler {
+
+	return connect.NewUnaryHandler(method, func(ctx context.Context, req *connect.Request[Req]) (*connect.Response[Res], error) {
+		ctx, stream := incomingContext(ctx, method, req.Header(), req.Peer().Addr)
+
+		resp, err := interceptor(ctx, req.Msg, &grpc.UnaryServerInfo{Server: server, FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(ctx, req.(*Req))
+			})
+		if err != nil {
+			st := status.Convert(err)
+			cerr := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+			stream.copyHeader(cerr.Meta())
+			return nil, cerr
+		}
+
+		out := connect.NewResponse(resp.(*Res))
+		stream.copyHeader(out.Header())
+		return out, nil
+	}, opts...)
+}
+
+// CORS wraps h so browsers on the configured origins can call it with the
+// headers the Connect and gRPC-Web protocols need.
+func CORS(cfg config.CORSConfig, h http.Handler) http.Handler {
+	return cors.New(cors.Options{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowCredentials: cfg.AllowCredentials,
+		AllowedMethods:   connectcors.AllowedMethods(),
+		AllowedHeaders:   append(connectcors.AllowedHeaders(), grpctransport.RequestIDHeader),
+		ExposedHeaders:   append(connectcors.ExposedHeaders(), grpctransport.RequestIDHeader),
+		MaxAge:           int(cfg.MaxAge / time.Second),
+	}).Handler(h)
+}
+
+// SplitGRPC sends native gRPC requests to grpcHandler, usually a
+// *grpc.Server, and everything else, including gRPC-Web, to web.
+//
+// It lets one listener serve native gRPC over HTTP/2 next to HTTP/1.1 and
+// HTTP/2 browser traffic.
+func SplitGRPC(grpcHandler, web http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isNativeGRPC(r) {
+			grpcHandler.ServeHTTP(w, r)
+			return
+		}
+		web.ServeHTTP(w, r)
+	})
+}
+
+func isNativeGRPC(r *http.Request) bool {
+	ct := r.Header.Get("Content-Type")
+	return r.ProtoMajor == 2 &&
+		strings.HasPrefix(ct, "application/grpc") &&
+		!strings.HasPrefix(ct, "application/grpc-web")
+}
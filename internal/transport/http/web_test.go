@@ -0,0 +1,146 @@
+package httptransport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"grpc-blog/internal/app/blog"
+	"grpc-blog/internal/config"
+	grpctransport "grpc-blog/internal/transport/grpc"
+	"grpc-blog/proto/blogpb"
+)
+
+// newTestWebServer serves native gRPC and the web handler on one h2c
+// listener, as cmd/server does.
+func newTestWebServer(t *testing.T, cors config.CORSConfig, interceptors ...grpc.UnaryServerInterceptor) *httptest.Server {
+	t.Helper()
+
+	blogServer := grpctransport.NewBlogGRPCServer(blog.NewService(zaptest.NewLogger(t), blog.NewMemoryStore()))
+
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	blogpb.RegisterBlogServiceServer(grpcServer, blogServer)
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	server := httptest.NewUnstartedServer(SplitGRPC(grpcServer, CORS(cors, NewWebHandler(blogServer, interceptors...))))
+	server.Config.Protocols = protocols
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestWebProtocols(t *testing.T) {
+	server := newTestWebServer(t, config.CORSConfig{})
+	url := server.URL + blogpb.BlogService_CreatePost_FullMethodName
+
+	cases := map[string][]connect.ClientOption{
+		"connect-json":  {connect.WithProtoJSON()},
+		"connect-proto": nil,
+		"grpc-web":      {connect.WithGRPCWeb()},
+	}
+
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := connect.NewClient[blogpb.CreatePostRequest, blogpb.PostResponse](http.DefaultClient, url, opts...)
+
+			resp, err := client.CallUnary(context.Background(), connect.NewRequest(&blogpb.CreatePostRequest{Title: name}))
+			if err != nil {
+				t.Fatalf("call failed: %v", err)
+			}
+			if len(resp.Msg.Post) != 1 || resp.Msg.Post[0].Title != name {
+				t.Fatalf("unexpected response %v", resp.Msg)
+			}
+		})
+	}
+}
+
+func TestWebServesNativeGRPC(t *testing.T) {
+	server := newTestWebServer(t, config.CORSConfig{})
+
+	conn, err := grpc.NewClient(strings.TrimPrefix(server.URL, "http://"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := blogpb.NewBlogServiceClient(conn).CreatePost(ctx, &blogpb.CreatePostRequest{Title: "native"})
+	if err != nil {
+		t.Fatalf("native gRPC call failed: %v", err)
+	}
+	if resp.Post[0].Title != "native" {
+		t.Fatalf("unexpected response %v", resp)
+	}
+}
+
+func TestWebSharesInterceptors(t *testing.T) {
+	deny := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if info.FullMethod == blogpb.BlogService_DeletePost_FullMethodName {
+			return nil, status.Error(codes.PermissionDenied, "read only")
+		}
+		return handler(ctx, req)
+	}
+
+	server := newTestWebServer(t, config.CORSConfig{}, grpctransport.UnaryRequestIDInterceptor(zap.NewNop()), deny)
+
+	client := connect.NewClient[blogpb.DeletePostRequest, blogpb.DeletePostResponse](
+		http.DefaultClient, server.URL+blogpb.BlogService_DeletePost_FullMethodName)
+
+	req := connect.NewRequest(&blogpb.DeletePostRequest{PostId: "any"})
+	req.Header().Set("X-Request-Id", "req-9")
+
+	_, err := client.CallUnary(context.Background(), req)
+
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) || cerr.Code() != connect.CodePermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+	if got := cerr.Meta().Get("X-Request-Id"); got != "req-9" {
+		t.Fatalf("expected request id on the error, got %q", got)
+	}
+}
+
+func TestWebCORS(t *testing.T) {
+	server := newTestWebServer(t, config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, MaxAge: time.Minute})
+	url := server.URL + blogpb.BlogService_ReadAll_FullMethodName
+
+	preflight := func(origin string) *http.Response {
+		return do(t, http.MethodOptions, url, "",
+			"Origin", origin,
+			"Access-Control-Request-Method", http.MethodPost,
+			"Access-Control-Request-Headers", "connect-protocol-version,content-type",
+		)
+	}
+
+	resp := preflight("https://app.example.com")
+	if resp.Header.Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Fatalf("expected allowed origin, got headers %v", resp.Header)
+	}
+	if resp.Header.Get("Access-Control-Max-Age") != "60" {
+		t.Fatalf("unexpected max age %q", resp.Header.Get("Access-Control-Max-Age"))
+	}
+
+	resp = preflight("https://evil.example.com")
+	if resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Fatal("unexpected CORS grant for unknown origin")
+	}
+}
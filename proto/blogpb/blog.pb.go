@@ -9,6 +9,7 @@ package blogpb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -22,6 +23,61 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ContentFormat says how a post's content is written. Unspecified content
+// is treated as plain text.
+type ContentFormat int32
+
+const (
+	ContentFormat_CONTENT_FORMAT_UNSPECIFIED ContentFormat = 0
+	ContentFormat_CONTENT_FORMAT_PLAIN       ContentFormat = 1
+	ContentFormat_CONTENT_FORMAT_MARKDOWN    ContentFormat = 2
+	// HTML is sanitized against an allowlist when the post is written.
+	ContentFormat_CONTENT_FORMAT_HTML ContentFormat = 3
+)
+
+// Enum value maps for ContentFormat.
+var (
+	ContentFormat_name = map[int32]string{
+		0: "CONTENT_FORMAT_UNSPECIFIED",
+		1: "CONTENT_FORMAT_PLAIN",
+		2: "CONTENT_FORMAT_MARKDOWN",
+		3: "CONTENT_FORMAT_HTML",
+	}
+	ContentFormat_value = map[string]int32{
+		"CONTENT_FORMAT_UNSPECIFIED": 0,
+		"CONTENT_FORMAT_PLAIN":       1,
+		"CONTENT_FORMAT_MARKDOWN":    2,
+		"CONTENT_FORMAT_HTML":        3,
+	}
+)
+
+func (x ContentFormat) Enum() *ContentFormat {
+	p := new(ContentFormat)
+	*p = x
+	return p
+}
+
+func (x ContentFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ContentFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_blog_proto_enumTypes[0].Descriptor()
+}
+
+func (ContentFormat) Type() protoreflect.EnumType {
+	return &file_proto_blog_proto_enumTypes[0]
+}
+
+func (x ContentFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ContentFormat.Descriptor instead.
+func (ContentFormat) EnumDescriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{0}
+}
+
 type BlogPost struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	PostId          string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
@@ -30,8 +86,15 @@ type BlogPost struct {
 	Author          string                 `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
 	PublicationDate *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=publication_date,json=publicationDate,proto3" json:"publication_date,omitempty"`
 	Tags            []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// Optional, unique URL-friendly key such as "hello-world": lower case
+	// letters, digits and single inner dashes.
+	Slug          string        `protobuf:"bytes,7,opt,name=slug,proto3" json:"slug,omitempty"`
+	ContentFormat ContentFormat `protobuf:"varint,8,opt,name=content_format,json=contentFormat,proto3,enum=blog.ContentFormat" json:"content_format,omitempty"`
+	// Output only: the content rendered to sanitized HTML, set by the server
+	// whenever the post is written.
+	RenderedHtml  string `protobuf:"bytes,9,opt,name=rendered_html,json=renderedHtml,proto3" json:"rendered_html,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BlogPost) Reset() {
@@ -106,6 +169,27 @@ func (x *BlogPost) GetTags() []string {
 	return nil
 }
 
+func (x *BlogPost) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *BlogPost) GetContentFormat() ContentFormat {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ContentFormat_CONTENT_FORMAT_UNSPECIFIED
+}
+
+func (x *BlogPost) GetRenderedHtml() string {
+	if x != nil {
+		return x.RenderedHtml
+	}
+	return ""
+}
+
 type CreatePostRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	Title           string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
@@ -113,6 +197,8 @@ type CreatePostRequest struct {
 	Author          string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
 	PublicationDate *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=publication_date,json=publicationDate,proto3" json:"publication_date,omitempty"`
 	Tags            []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Slug            string                 `protobuf:"bytes,6,opt,name=slug,proto3" json:"slug,omitempty"`
+	ContentFormat   ContentFormat          `protobuf:"varint,7,opt,name=content_format,json=contentFormat,proto3,enum=blog.ContentFormat" json:"content_format,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -182,10 +268,26 @@ func (x *CreatePostRequest) GetTags() []string {
 	return nil
 }
 
+func (x *CreatePostRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *CreatePostRequest) GetContentFormat() ContentFormat {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ContentFormat_CONTENT_FORMAT_UNSPECIFIED
+}
+
 type PostResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Post          []*BlogPost            `protobuf:"bytes,1,rep,name=post,proto3" json:"post,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Post  []*BlogPost            `protobuf:"bytes,1,rep,name=post,proto3" json:"post,omitempty"`
+	Error string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// Set by ReadAll when more posts follow; pass it as page_token.
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -234,6 +336,13 @@ func (x *PostResponse) GetError() string {
 	return ""
 }
 
+func (x *PostResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type ReadPostRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
@@ -278,8 +387,12 @@ func (x *ReadPostRequest) GetPostId() string {
 	return ""
 }
 
+// ReadAllRequest lists posts ordered by post_id. With page_size 0 every
+// post after page_token is returned.
 type ReadAllRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -314,6 +427,23 @@ func (*ReadAllRequest) Descriptor() ([]byte, []int) {
 	return file_proto_blog_proto_rawDescGZIP(), []int{4}
 }
 
+func (x *ReadAllRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ReadAllRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// UpdatePostRequest changes the fields named in update_mask, or every
+// field the request carries when the mask is empty; an unset
+// publication_date is then kept.
 type UpdatePostRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
@@ -321,8 +451,15 @@ type UpdatePostRequest struct {
 	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
 	Author        string                 `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
 	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Slug          string                 `protobuf:"bytes,6,opt,name=slug,proto3" json:"slug,omitempty"`
+	ContentFormat ContentFormat          `protobuf:"varint,7,opt,name=content_format,json=contentFormat,proto3,enum=blog.ContentFormat" json:"content_format,omitempty"`
+	// Field names such as "title" or "content_format". A named field left
+	// unset in the request is cleared.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,8,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Kept when unset, unless named in update_mask.
+	PublicationDate *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=publication_date,json=publicationDate,proto3" json:"publication_date,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *UpdatePostRequest) Reset() {
@@ -390,6 +527,34 @@ func (x *UpdatePostRequest) GetTags() []string {
 	return nil
 }
 
+func (x *UpdatePostRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *UpdatePostRequest) GetContentFormat() ContentFormat {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ContentFormat_CONTENT_FORMAT_UNSPECIFIED
+}
+
+func (x *UpdatePostRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdatePostRequest) GetPublicationDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublicationDate
+	}
+	return nil
+}
+
 type DeletePostRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PostId        string                 `protobuf:"bytes,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
@@ -486,41 +651,364 @@ func (x *DeletePostResponse) GetError() string {
 	return ""
 }
 
+// ExportPostsRequest streams every post ordered by post_id, one post per
+// message.
+type ExportPostsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportPostsRequest) Reset() {
+	*x = ExportPostsRequest{}
+	mi := &file_proto_blog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportPostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportPostsRequest) ProtoMessage() {}
+
+func (x *ExportPostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportPostsRequest.ProtoReflect.Descriptor instead.
+func (*ExportPostsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{8}
+}
+
+// Backup describes a backup file on the server.
+type Backup struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// File name within the server's backup directory.
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	PostCount int64                  `protobuf:"varint,3,opt,name=post_count,json=postCount,proto3" json:"post_count,omitempty"`
+	SizeBytes int64                  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	// Hex SHA-256 of the posts in the file, checked on restore.
+	Sha256 string `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// Version of the file format; a server restores versions up to its own.
+	FormatVersion int32 `protobuf:"varint,6,opt,name=format_version,json=formatVersion,proto3" json:"format_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Backup) Reset() {
+	*x = Backup{}
+	mi := &file_proto_blog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Backup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Backup) ProtoMessage() {}
+
+func (x *Backup) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Backup.ProtoReflect.Descriptor instead.
+func (*Backup) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Backup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Backup) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Backup) GetPostCount() int64 {
+	if x != nil {
+		return x.PostCount
+	}
+	return 0
+}
+
+func (x *Backup) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *Backup) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *Backup) GetFormatVersion() int32 {
+	if x != nil {
+		return x.FormatVersion
+	}
+	return 0
+}
+
+type CreateBackupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBackupRequest) Reset() {
+	*x = CreateBackupRequest{}
+	mi := &file_proto_blog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBackupRequest) ProtoMessage() {}
+
+func (x *CreateBackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBackupRequest.ProtoReflect.Descriptor instead.
+func (*CreateBackupRequest) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{10}
+}
+
+type ListBackupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBackupsRequest) Reset() {
+	*x = ListBackupsRequest{}
+	mi := &file_proto_blog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBackupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBackupsRequest) ProtoMessage() {}
+
+func (x *ListBackupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBackupsRequest.ProtoReflect.Descriptor instead.
+func (*ListBackupsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{11}
+}
+
+// ListBackupsResponse lists backups newest first.
+type ListBackupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backups       []*Backup              `protobuf:"bytes,1,rep,name=backups,proto3" json:"backups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBackupsResponse) Reset() {
+	*x = ListBackupsResponse{}
+	mi := &file_proto_blog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBackupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBackupsResponse) ProtoMessage() {}
+
+func (x *ListBackupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBackupsResponse.ProtoReflect.Descriptor instead.
+func (*ListBackupsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListBackupsResponse) GetBackups() []*Backup {
+	if x != nil {
+		return x.Backups
+	}
+	return nil
+}
+
+type RestoreBackupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreBackupRequest) Reset() {
+	*x = RestoreBackupRequest{}
+	mi := &file_proto_blog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreBackupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreBackupRequest) ProtoMessage() {}
+
+func (x *RestoreBackupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreBackupRequest.ProtoReflect.Descriptor instead.
+func (*RestoreBackupRequest) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RestoreBackupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
 var File_proto_blog_proto protoreflect.FileDescriptor
 
 const file_proto_blog_proto_rawDesc = "" +
 	"\n" +
-	"\x10proto/blog.proto\x12\x04blog\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc6\x01\n" +
+	"\x10proto/blog.proto\x12\x04blog\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbb\x02\n" +
 	"\bBlogPost\x12\x17\n" +
 	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x12\x16\n" +
 	"\x06author\x18\x04 \x01(\tR\x06author\x12E\n" +
 	"\x10publication_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0fpublicationDate\x12\x12\n" +
-	"\x04tags\x18\x06 \x03(\tR\x04tags\"\xb6\x01\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12\x12\n" +
+	"\x04slug\x18\a \x01(\tR\x04slug\x12:\n" +
+	"\x0econtent_format\x18\b \x01(\x0e2\x13.blog.ContentFormatR\rcontentFormat\x12#\n" +
+	"\rrendered_html\x18\t \x01(\tR\frenderedHtml\"\x86\x02\n" +
 	"\x11CreatePostRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x16\n" +
 	"\x06author\x18\x03 \x01(\tR\x06author\x12E\n" +
 	"\x10publication_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0fpublicationDate\x12\x12\n" +
-	"\x04tags\x18\x05 \x03(\tR\x04tags\"H\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x12\n" +
+	"\x04slug\x18\x06 \x01(\tR\x04slug\x12:\n" +
+	"\x0econtent_format\x18\a \x01(\x0e2\x13.blog.ContentFormatR\rcontentFormat\"p\n" +
 	"\fPostResponse\x12\"\n" +
 	"\x04post\x18\x01 \x03(\v2\x0e.blog.BlogPostR\x04post\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"*\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"*\n" +
 	"\x0fReadPostRequest\x12\x17\n" +
-	"\apost_id\x18\x01 \x01(\tR\x06postId\"\x10\n" +
-	"\x0eReadAllRequest\"\x88\x01\n" +
+	"\apost_id\x18\x01 \x01(\tR\x06postId\"L\n" +
+	"\x0eReadAllRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"\xdc\x02\n" +
 	"\x11UpdatePostRequest\x12\x17\n" +
 	"\apost_id\x18\x01 \x01(\tR\x06postId\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x12\x16\n" +
 	"\x06author\x18\x04 \x01(\tR\x06author\x12\x12\n" +
-	"\x04tags\x18\x05 \x03(\tR\x04tags\",\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x12\n" +
+	"\x04slug\x18\x06 \x01(\tR\x04slug\x12:\n" +
+	"\x0econtent_format\x18\a \x01(\x0e2\x13.blog.ContentFormatR\rcontentFormat\x12;\n" +
+	"\vupdate_mask\x18\b \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12E\n" +
+	"\x10publication_date\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x0fpublicationDate\",\n" +
 	"\x11DeletePostRequest\x12\x17\n" +
 	"\apost_id\x18\x01 \x01(\tR\x06postId\"D\n" +
 	"\x12DeletePostResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error2\xb0\x02\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x14\n" +
+	"\x12ExportPostsRequest\"\xd4\x01\n" +
+	"\x06Backup\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"post_count\x18\x03 \x01(\x03R\tpostCount\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x04 \x01(\x03R\tsizeBytes\x12\x16\n" +
+	"\x06sha256\x18\x05 \x01(\tR\x06sha256\x12%\n" +
+	"\x0eformat_version\x18\x06 \x01(\x05R\rformatVersion\"\x15\n" +
+	"\x13CreateBackupRequest\"\x14\n" +
+	"\x12ListBackupsRequest\"=\n" +
+	"\x13ListBackupsResponse\x12&\n" +
+	"\abackups\x18\x01 \x03(\v2\f.blog.BackupR\abackups\"*\n" +
+	"\x14RestoreBackupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name*\x7f\n" +
+	"\rContentFormat\x12\x1e\n" +
+	"\x1aCONTENT_FORMAT_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14CONTENT_FORMAT_PLAIN\x10\x01\x12\x1b\n" +
+	"\x17CONTENT_FORMAT_MARKDOWN\x10\x02\x12\x17\n" +
+	"\x13CONTENT_FORMAT_HTML\x10\x032\xeb\x02\n" +
 	"\vBlogService\x129\n" +
 	"\n" +
 	"CreatePost\x12\x17.blog.CreatePostRequest\x1a\x12.blog.PostResponse\x125\n" +
@@ -529,7 +1017,12 @@ const file_proto_blog_proto_rawDesc = "" +
 	"UpdatePost\x12\x17.blog.UpdatePostRequest\x1a\x12.blog.PostResponse\x12?\n" +
 	"\n" +
 	"DeletePost\x12\x17.blog.DeletePostRequest\x1a\x18.blog.DeletePostResponse\x123\n" +
-	"\aReadAll\x12\x14.blog.ReadAllRequest\x1a\x12.blog.PostResponseB\x1fZ\x1dgrpc-blog/proto/blogpb;blogpbb\x06proto3"
+	"\aReadAll\x12\x14.blog.ReadAllRequest\x1a\x12.blog.PostResponse\x129\n" +
+	"\vExportPosts\x12\x18.blog.ExportPostsRequest\x1a\x0e.blog.BlogPost0\x012\xc6\x01\n" +
+	"\fAdminService\x127\n" +
+	"\fCreateBackup\x12\x19.blog.CreateBackupRequest\x1a\f.blog.Backup\x12B\n" +
+	"\vListBackups\x12\x18.blog.ListBackupsRequest\x1a\x19.blog.ListBackupsResponse\x129\n" +
+	"\rRestoreBackup\x12\x1a.blog.RestoreBackupRequest\x1a\f.blog.BackupB\x1fZ\x1dgrpc-blog/proto/blogpb;blogpbb\x06proto3"
 
 var (
 	file_proto_blog_proto_rawDescOnce sync.Once
@@ -543,37 +1036,61 @@ func file_proto_blog_proto_rawDescGZIP() []byte {
 	return file_proto_blog_proto_rawDescData
 }
 
-var file_proto_blog_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_blog_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_blog_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_proto_blog_proto_goTypes = []any{
-	(*BlogPost)(nil),              // 0: blog.BlogPost
-	(*CreatePostRequest)(nil),     // 1: blog.CreatePostRequest
-	(*PostResponse)(nil),          // 2: blog.PostResponse
-	(*ReadPostRequest)(nil),       // 3: blog.ReadPostRequest
-	(*ReadAllRequest)(nil),        // 4: blog.ReadAllRequest
-	(*UpdatePostRequest)(nil),     // 5: blog.UpdatePostRequest
-	(*DeletePostRequest)(nil),     // 6: blog.DeletePostRequest
-	(*DeletePostResponse)(nil),    // 7: blog.DeletePostResponse
-	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(ContentFormat)(0),            // 0: blog.ContentFormat
+	(*BlogPost)(nil),              // 1: blog.BlogPost
+	(*CreatePostRequest)(nil),     // 2: blog.CreatePostRequest
+	(*PostResponse)(nil),          // 3: blog.PostResponse
+	(*ReadPostRequest)(nil),       // 4: blog.ReadPostRequest
+	(*ReadAllRequest)(nil),        // 5: blog.ReadAllRequest
+	(*UpdatePostRequest)(nil),     // 6: blog.UpdatePostRequest
+	(*DeletePostRequest)(nil),     // 7: blog.DeletePostRequest
+	(*DeletePostResponse)(nil),    // 8: blog.DeletePostResponse
+	(*ExportPostsRequest)(nil),    // 9: blog.ExportPostsRequest
+	(*Backup)(nil),                // 10: blog.Backup
+	(*CreateBackupRequest)(nil),   // 11: blog.CreateBackupRequest
+	(*ListBackupsRequest)(nil),    // 12: blog.ListBackupsRequest
+	(*ListBackupsResponse)(nil),   // 13: blog.ListBackupsResponse
+	(*RestoreBackupRequest)(nil),  // 14: blog.RestoreBackupRequest
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil), // 16: google.protobuf.FieldMask
 }
 var file_proto_blog_proto_depIdxs = []int32{
-	8, // 0: blog.BlogPost.publication_date:type_name -> google.protobuf.Timestamp
-	8, // 1: blog.CreatePostRequest.publication_date:type_name -> google.protobuf.Timestamp
-	0, // 2: blog.PostResponse.post:type_name -> blog.BlogPost
-	1, // 3: blog.BlogService.CreatePost:input_type -> blog.CreatePostRequest
-	3, // 4: blog.BlogService.ReadPost:input_type -> blog.ReadPostRequest
-	5, // 5: blog.BlogService.UpdatePost:input_type -> blog.UpdatePostRequest
-	6, // 6: blog.BlogService.DeletePost:input_type -> blog.DeletePostRequest
-	4, // 7: blog.BlogService.ReadAll:input_type -> blog.ReadAllRequest
-	2, // 8: blog.BlogService.CreatePost:output_type -> blog.PostResponse
-	2, // 9: blog.BlogService.ReadPost:output_type -> blog.PostResponse
-	2, // 10: blog.BlogService.UpdatePost:output_type -> blog.PostResponse
-	7, // 11: blog.BlogService.DeletePost:output_type -> blog.DeletePostResponse
-	2, // 12: blog.BlogService.ReadAll:output_type -> blog.PostResponse
-	8, // [8:13] is the sub-list for method output_type
-	3, // [3:8] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	15, // 0: blog.BlogPost.publication_date:type_name -> google.protobuf.Timestamp
+	0,  // 1: blog.BlogPost.content_format:type_name -> blog.ContentFormat
+	15, // 2: blog.CreatePostRequest.publication_date:type_name -> google.protobuf.Timestamp
+	0,  // 3: blog.CreatePostRequest.content_format:type_name -> blog.ContentFormat
+	1,  // 4: blog.PostResponse.post:type_name -> blog.BlogPost
+	0,  // 5: blog.UpdatePostRequest.content_format:type_name -> blog.ContentFormat
+	16, // 6: blog.UpdatePostRequest.update_mask:type_name -> google.protobuf.FieldMask
+	15, // 7: blog.UpdatePostRequest.publication_date:type_name -> google.protobuf.Timestamp
+	15, // 8: blog.Backup.created_at:type_name -> google.protobuf.Timestamp
+	10, // 9: blog.ListBackupsResponse.backups:type_name -> blog.Backup
+	2,  // 10: blog.BlogService.CreatePost:input_type -> blog.CreatePostRequest
+	4,  // 11: blog.BlogService.ReadPost:input_type -> blog.ReadPostRequest
+	6,  // 12: blog.BlogService.UpdatePost:input_type -> blog.UpdatePostRequest
+	7,  // 13: blog.BlogService.DeletePost:input_type -> blog.DeletePostRequest
+	5,  // 14: blog.BlogService.ReadAll:input_type -> blog.ReadAllRequest
+	9,  // 15: blog.BlogService.ExportPosts:input_type -> blog.ExportPostsRequest
+	11, // 16: blog.AdminService.CreateBackup:input_type -> blog.CreateBackupRequest
+	12, // 17: blog.AdminService.ListBackups:input_type -> blog.ListBackupsRequest
+	14, // 18: blog.AdminService.RestoreBackup:input_type -> blog.RestoreBackupRequest
+	3,  // 19: blog.BlogService.CreatePost:output_type -> blog.PostResponse
+	3,  // 20: blog.BlogService.ReadPost:output_type -> blog.PostResponse
+	3,  // 21: blog.BlogService.UpdatePost:output_type -> blog.PostResponse
+	8,  // 22: blog.BlogService.DeletePost:output_type -> blog.DeletePostResponse
+	3,  // 23: blog.BlogService.ReadAll:output_type -> blog.PostResponse
+	1,  // 24: blog.BlogService.ExportPosts:output_type -> blog.BlogPost
+	10, // 25: blog.AdminService.CreateBackup:output_type -> blog.Backup
+	13, // 26: blog.AdminService.ListBackups:output_type -> blog.ListBackupsResponse
+	10, // 27: blog.AdminService.RestoreBackup:output_type -> blog.Backup
+	19, // [19:28] is the sub-list for method output_type
+	10, // [10:19] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_proto_blog_proto_init() }
@@ -586,13 +1103,14 @@ func file_proto_blog_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_blog_proto_rawDesc), len(file_proto_blog_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      1,
+			NumMessages:   14,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_blog_proto_goTypes,
 		DependencyIndexes: file_proto_blog_proto_depIdxs,
+		EnumInfos:         file_proto_blog_proto_enumTypes,
 		MessageInfos:      file_proto_blog_proto_msgTypes,
 	}.Build()
 	File_proto_blog_proto = out.File
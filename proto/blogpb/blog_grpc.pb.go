@@ -19,11 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BlogService_CreatePost_FullMethodName = "/blog.BlogService/CreatePost"
-	BlogService_ReadPost_FullMethodName   = "/blog.BlogService/ReadPost"
-	BlogService_UpdatePost_FullMethodName = "/blog.BlogService/UpdatePost"
-	BlogService_DeletePost_FullMethodName = "/blog.BlogService/DeletePost"
-	BlogService_ReadAll_FullMethodName    = "/blog.BlogService/ReadAll"
+	BlogService_CreatePost_FullMethodName  = "/blog.BlogService/CreatePost"
+	BlogService_ReadPost_FullMethodName    = "/blog.BlogService/ReadPost"
+	BlogService_UpdatePost_FullMethodName  = "/blog.BlogService/UpdatePost"
+	BlogService_DeletePost_FullMethodName  = "/blog.BlogService/DeletePost"
+	BlogService_ReadAll_FullMethodName     = "/blog.BlogService/ReadAll"
+	BlogService_ExportPosts_FullMethodName = "/blog.BlogService/ExportPosts"
 )
 
 // BlogServiceClient is the client API for BlogService service.
@@ -35,6 +36,7 @@ type BlogServiceClient interface {
 	UpdatePost(ctx context.Context, in *UpdatePostRequest, opts ...grpc.CallOption) (*PostResponse, error)
 	DeletePost(ctx context.Context, in *DeletePostRequest, opts ...grpc.CallOption) (*DeletePostResponse, error)
 	ReadAll(ctx context.Context, in *ReadAllRequest, opts ...grpc.CallOption) (*PostResponse, error)
+	ExportPosts(ctx context.Context, in *ExportPostsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BlogPost], error)
 }
 
 type blogServiceClient struct {
@@ -95,6 +97,25 @@ func (c *blogServiceClient) ReadAll(ctx context.Context, in *ReadAllRequest, opt
 	return out, nil
 }
 
+func (c *blogServiceClient) ExportPosts(ctx context.Context, in *ExportPostsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BlogPost], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BlogService_ServiceDesc.Streams[0], BlogService_ExportPosts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportPostsRequest, BlogPost]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BlogService_ExportPostsClient = grpc.ServerStreamingClient[BlogPost]
+
 // BlogServiceServer is the server API for BlogService service.
 // All implementations must embed UnimplementedBlogServiceServer
 // for forward compatibility.
@@ -104,6 +125,7 @@ type BlogServiceServer interface {
 	UpdatePost(context.Context, *UpdatePostRequest) (*PostResponse, error)
 	DeletePost(context.Context, *DeletePostRequest) (*DeletePostResponse, error)
 	ReadAll(context.Context, *ReadAllRequest) (*PostResponse, error)
+	ExportPosts(*ExportPostsRequest, grpc.ServerStreamingServer[BlogPost]) error
 	mustEmbedUnimplementedBlogServiceServer()
 }
 
@@ -129,6 +151,9 @@ func (UnimplementedBlogServiceServer) DeletePost(context.Context, *DeletePostReq
 func (UnimplementedBlogServiceServer) ReadAll(context.Context, *ReadAllRequest) (*PostResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ReadAll not implemented")
 }
+func (UnimplementedBlogServiceServer) ExportPosts(*ExportPostsRequest, grpc.ServerStreamingServer[BlogPost]) error {
+	return status.Error(codes.Unimplemented, "method ExportPosts not implemented")
+}
 func (UnimplementedBlogServiceServer) mustEmbedUnimplementedBlogServiceServer() {}
 func (UnimplementedBlogServiceServer) testEmbeddedByValue()                     {}
 
@@ -240,6 +265,17 @@ func _BlogService_ReadAll_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlogService_ExportPosts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportPostsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlogServiceServer).ExportPosts(m, &grpc.GenericServerStream[ExportPostsRequest, BlogPost]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BlogService_ExportPostsServer = grpc.ServerStreamingServer[BlogPost]
+
 // BlogService_ServiceDesc is the grpc.ServiceDesc for BlogService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -268,6 +304,202 @@ var BlogService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _BlogService_ReadAll_Handler,
 		},
 	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportPosts",
+			Handler:       _BlogService_ExportPosts_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/blog.proto",
+}
+
+const (
+	AdminService_CreateBackup_FullMethodName  = "/blog.AdminService/CreateBackup"
+	AdminService_ListBackups_FullMethodName   = "/blog.AdminService/ListBackups"
+	AdminService_RestoreBackup_FullMethodName = "/blog.AdminService/RestoreBackup"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService is served only when the server has a backup directory.
+// Errors are gRPC status codes rather than in-band strings.
+type AdminServiceClient interface {
+	// CreateBackup writes every post, as of one instant, to a new file.
+	CreateBackup(ctx context.Context, in *CreateBackupRequest, opts ...grpc.CallOption) (*Backup, error)
+	ListBackups(ctx context.Context, in *ListBackupsRequest, opts ...grpc.CallOption) (*ListBackupsResponse, error)
+	// RestoreBackup verifies a backup and replaces every post with its
+	// posts in one step.
+	RestoreBackup(ctx context.Context, in *RestoreBackupRequest, opts ...grpc.CallOption) (*Backup, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) CreateBackup(ctx context.Context, in *CreateBackupRequest, opts ...grpc.CallOption) (*Backup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Backup)
+	err := c.cc.Invoke(ctx, AdminService_CreateBackup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListBackups(ctx context.Context, in *ListBackupsRequest, opts ...grpc.CallOption) (*ListBackupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBackupsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListBackups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RestoreBackup(ctx context.Context, in *RestoreBackupRequest, opts ...grpc.CallOption) (*Backup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Backup)
+	err := c.cc.Invoke(ctx, AdminService_RestoreBackup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService is served only when the server has a backup directory.
+// Errors are gRPC status codes rather than in-band strings.
+type AdminServiceServer interface {
+	// CreateBackup writes every post, as of one instant, to a new file.
+	CreateBackup(context.Context, *CreateBackupRequest) (*Backup, error)
+	ListBackups(context.Context, *ListBackupsRequest) (*ListBackupsResponse, error)
+	// RestoreBackup verifies a backup and replaces every post with its
+	// posts in one step.
+	RestoreBackup(context.Context, *RestoreBackupRequest) (*Backup, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) CreateBackup(context.Context, *CreateBackupRequest) (*Backup, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateBackup not implemented")
+}
+func (UnimplementedAdminServiceServer) ListBackups(context.Context, *ListBackupsRequest) (*ListBackupsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListBackups not implemented")
+}
+func (UnimplementedAdminServiceServer) RestoreBackup(context.Context, *RestoreBackupRequest) (*Backup, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestoreBackup not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call panics, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_CreateBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateBackup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateBackup(ctx, req.(*CreateBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBackupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListBackups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListBackups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListBackups(ctx, req.(*ListBackupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RestoreBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RestoreBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RestoreBackup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RestoreBackup(ctx, req.(*RestoreBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "blog.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateBackup",
+			Handler:    _AdminService_CreateBackup_Handler,
+		},
+		{
+			MethodName: "ListBackups",
+			Handler:    _AdminService_ListBackups_Handler,
+		},
+		{
+			MethodName: "RestoreBackup",
+			Handler:    _AdminService_RestoreBackup_Handler,
+		},
+	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/blog.proto",
 }